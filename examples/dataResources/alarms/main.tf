@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    xrcm = {
+      source = "infinera.com/poc/xrcm"
+    }
+  }
+}
+
+provider "xrcm" {
+  username = "dev"
+  password = "xrSysArch3"
+  host     = "https://sv-kube-prd.infinera.com:443"
+}
+
+data "xrcm_alarms" "critical" {
+  modulealarms  = [ {n="xr-regA_H1-Hub"}, {n="xr-regA_H1-L1"} ]
+  severities    = ["critical", "major"]
+  resourcetypes = ["xrcm_carrier", "xrcm_lc"]
+  min_age       = "2m"
+
+  lifecycle {
+    postcondition {
+      condition     = alltrue([for m in self.modulealarms : length(m.alarms) == 0])
+      error_message = "Critical alarms remain after the change."
+    }
+  }
+}
+
+output "alarms" {
+  value = data.xrcm_alarms.critical
+}
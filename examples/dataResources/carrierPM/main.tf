@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    xrcm = {
+      source = "infinera.com/poc/xrcm"
+    }
+  }
+}
+
+provider "xrcm" {
+  username = "dev"
+  password = "xrSysArch3"
+  host     = "https://sv-kube-prd.infinera.com:443"
+}
+
+data "xrcm_carrier_pm" "pm" {
+  modulepms = [ {n="xr-regA_H1-Hub", lineptpid="1", carrierids=["1"]},
+  {n="xr-regA_H1-L1", lineptpid="1"}]
+
+  lifecycle {
+    postcondition {
+      condition     = alltrue([for m in self.modulepms : alltrue([for p in m.pms : p.metrics["qFactor"].current > 7.5])])
+      error_message = "Q-factor below threshold after turn-up."
+    }
+  }
+}
+
+output "pm" {
+  value = data.xrcm_carrier_pm.pm
+}
@@ -112,3 +112,33 @@ output "check_configs" {
 output "check_carriers" {
   value = data.xrcm_check_resources.check_carriers
 }
+
+data "xrcm_check_resources" "check_carrier_tolerances" {
+  report_path = "reports/check_carrier_tolerances.xml"
+  wait_until_match = true
+  timeout          = "10m"
+  poll_interval    = "30s"
+  queries = [ { n = "xr-regA_H1-Hub", resourcetype = "Carrier", resources = [ { parentid = "1", resourceid = "1", attributevalues = [
+    { attribute = "operatingFrequency", operator = "within", intentvalue = "193100000", tolerance = 1000 },
+    { attribute = "capabilities.maxBaud", operator = "gt", intentvalue = "0" },
+    { attribute = "txCDSCs", intentvalue = "[1,2,3,4]" },
+  ] } ] } ]
+}
+
+data "xrcm_check_resources" "check_otus" {
+  queries = [ { n = "xr-regA_H1-Hub", resourcetype = "xrcm_otu", resources = [ { resourceid = "1", attributevalues = [{ attribute = "adminState", intentvalue = "unlock" }] } ] },
+              { n = "xr-regA_H1-Hub", resources = [ { href = "/ethernets/1/lldp-cfg", attributevalues = [{ attribute = "adminStatus", intentvalue = "txAndRx" }] } ] } ]
+}
+
+data "xrcm_checks" "check_intent" {
+  checks = [ for q in data.xrcm_check_resources.check_carrier_tolerances.queries : {
+    condition   = anytrue([ for r in q.resources : anytrue([ for a in r.attributevalues : a.isvaluematch == false ]) ])
+    description = "carrier intent of ${q.n}"
+    throw       = "the carrier does not match its intent"
+    severity    = "warning"
+  } ]
+}
+
+output "check_intent" {
+  value = data.xrcm_checks.check_intent.results
+}
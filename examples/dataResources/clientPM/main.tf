@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    xrcm = {
+      source = "infinera.com/poc/xrcm"
+    }
+  }
+}
+
+provider "xrcm" {
+  username = "dev"
+  password = "xrSysArch3"
+  host     = "https://sv-kube-prd.infinera.com:443"
+}
+
+data "xrcm_ethernet_pm" "pm" {
+  modulepms = [ {n="xr-regA_H1-Hub", ethernetids=["1"]} ]
+}
+
+data "xrcm_otu_pm" "pm" {
+  modulepms = [ {n="xr-regA_H1-L1"} ]
+}
+
+output "ethernet_pm" {
+  value = data.xrcm_ethernet_pm.pm
+}
+
+output "otu_pm" {
+  value = data.xrcm_otu_pm.pm
+}
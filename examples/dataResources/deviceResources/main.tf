@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    xrcm = {
+      source = "infinera.com/poc/xrcm"
+    }
+  }
+}
+
+provider "xrcm" {
+  username = "dev"
+  password = "xrSysArch3"
+  host     = "https://sv-kube-prd.infinera.com:443"
+}
+
+data "xrcm_device_resources" "otus" {
+  names        = ["xr-regA_H1-Hub", "xr-regA_H1-L1"]
+  resourcetype = "xr.otu"
+  #hrefprefix  = "/lineptps/1/"
+}
+
+output "otus" {
+  value = { for r in data.xrcm_device_resources.otus.resources : r.id => jsondecode(r.content) }
+}
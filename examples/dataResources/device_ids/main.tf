@@ -17,6 +17,13 @@ data "xrcm_devices_ids" "devices_ids" {
   deviceids = [{n="xr-regA_H1-L2"}, {n="xr-regA_H1-L1"}]
 }
 
+// Device ids of the devices data source of the turn-up workspace, in its S3 state.
+data "xrcm_devices_ids" "remote_devices_ids" {
+  state     = "s3://network-tfstate/turnup/terraform.tfstate"
+  address   = "data.xrcm_detaildevices.devices"
+  deviceids = [{n="xr-regA_H1-L2"}]
+}
+
 output "xrcm_devices_ids" {
   value = data.xrcm_devices_ids.devices_ids
 }
\ No newline at end of file
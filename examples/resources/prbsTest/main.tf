@@ -0,0 +1,43 @@
+// Test Sample - to run a PRBS test between two DSCs
+
+terraform {
+  required_providers {
+    xrcm = {
+      source = "infinera.com/poc/xrcm"
+    }
+  }
+}
+
+provider "xrcm" {
+  username = "dev"
+  password = "xrSysArch3"
+  host     = "https://sv-kube-prd.infinera.com:443"
+}
+
+resource "xrcm_prbs_test" "prbs_test" {
+  generator = {
+    n         = "xr-regA_H1-Hub"
+    lineptpid = 1
+    carrierid = 1
+    dscid     = 1
+  }
+  monitor = {
+    n         = "xr-regA_H1-L1"
+    lineptpid = 1
+    carrierid = 1
+    dscid     = 1
+  }
+  soak_time = "60s"
+  max_ber   = 1e-12
+
+  lifecycle {
+    postcondition {
+      condition     = self.passed
+      error_message = "PRBS test failed: sync ${self.sync_status}, ${self.error_count} errors, ber ${self.ber}"
+    }
+  }
+}
+
+output "prbs_test" {
+  value = xrcm_prbs_test.prbs_test
+}
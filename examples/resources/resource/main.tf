@@ -0,0 +1,45 @@
+// Test Sample - to manage any XR device resource by href
+
+terraform {
+  required_providers {
+    xrcm = {
+      source = "infinera.com/poc/xrcm"
+    }
+  }
+}
+
+provider "xrcm" {
+  username = "dev"
+  password = "xrSysArch3"
+  host     = "https://sv-kube-prd.infinera.com:443"
+}
+
+// PUT to an existing resource; drift is checked on the keys in body
+resource "xrcm_resource" "cfg" {
+  n    = "xr-regA_H1-L1"
+  href = "/cfg"
+  body = jsonencode({
+    trafficMode = "L1Mode"
+  })
+}
+
+// POST to a collection on create, DELETE the created link on destroy
+resource "xrcm_resource" "ac" {
+  n    = "xr-regA_H1-L1"
+  href = "/ethernets/1/acs"
+  mode = "resource-links"
+  body = jsonencode({
+    rep = {
+      capacity = 1
+      imc      = "MatchAll"
+      emc      = "MatchAll"
+    }
+    if = ["oic.if.baseline", "oic.if.rw"]
+    rt = ["xr.ethernet.ac"]
+    p  = { bm = 1 }
+  })
+}
+
+output "xrcm_resource_cfg" {
+  value = xrcm_resource.cfg.content_map
+}
@@ -1,4 +1,4 @@
-module terraform-provider-xrcm
+module github.com/infinera/terraform-provider-xr
 
 go 1.19
 
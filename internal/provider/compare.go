@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operators of the attribute values of xrcm_check_resources.
+const (
+	operatorEq     = "eq"
+	operatorNe     = "ne"
+	operatorGt     = "gt"
+	operatorLt     = "lt"
+	operatorIn     = "in"
+	operatorRegex  = "regex"
+	operatorWithin = "within"
+)
+
+// lookupPath returns the value at a dot separated path of a resource content,
+// e.g. capabilities.maxBaud, a number addressing an element of a list.
+func lookupPath(content map[string]interface{}, path string) (interface{}, bool) {
+	var v interface{} = content
+	for _, key := range strings.Split(path, ".") {
+		switch c := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = c[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			v = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// formatValue returns the device value of an attribute as a string, lists and
+// objects as JSON.
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%.2f", value)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// compareValue reports whether the device value v compares with the intent
+// value by operator. Lists compare as sets, whatever the order of their
+// elements; the intent of a list or of in is a JSON list or comma separated.
+func compareValue(operator string, v interface{}, intent string, tolerance float64) (bool, error) {
+	switch operator {
+	case "", operatorEq:
+		return equalValue(v, intent), nil
+	case operatorNe:
+		return !equalValue(v, intent), nil
+	case operatorGt, operatorLt, operatorWithin:
+		value, ok := numberValue(v)
+		if !ok {
+			return false, fmt.Errorf("device value %s is not a number", formatValue(v))
+		}
+		want, err := strconv.ParseFloat(strings.TrimSpace(intent), 64)
+		if err != nil {
+			return false, fmt.Errorf("intent value %q is not a number", intent)
+		}
+		switch operator {
+		case operatorGt:
+			return value > want, nil
+		case operatorLt:
+			return value < want, nil
+		}
+		return math.Abs(value-want) <= tolerance, nil
+	case operatorIn:
+		for _, want := range intentList(intent) {
+			if equalValue(v, want) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case operatorRegex:
+		re, err := regexp.Compile(intent)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(formatValue(v)), nil
+	}
+	return false, errors.New("unknown operator " + operator + ", expected eq, ne, gt, lt, in, regex or within")
+}
+
+// equalValue reports whether the device value v equals the intent value, of
+// the type of v.
+func equalValue(v interface{}, intent string) bool {
+	switch value := v.(type) {
+	case float64:
+		want, err := strconv.ParseFloat(strings.TrimSpace(intent), 64)
+		return err == nil && value == want
+	case bool:
+		want, err := strconv.ParseBool(strings.TrimSpace(intent))
+		return err == nil && value == want
+	case string:
+		return value == intent
+	case []interface{}:
+		return equalSet(value, intentList(intent))
+	}
+	return formatValue(v) == intent
+}
+
+// equalSet reports whether the device list has the elements of the intent
+// list, as many times each, in any order.
+func equalSet(values []interface{}, intents []string) bool {
+	if len(values) != len(intents) {
+		return false
+	}
+	got := make([]string, len(values))
+	for i, v := range values {
+		got[i] = elementKey(v)
+	}
+	want := make([]string, len(intents))
+	for i, intent := range intents {
+		want[i] = intent
+		if f, err := strconv.ParseFloat(intent, 64); err == nil {
+			want[i] = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// elementKey returns the string a list element compares by.
+func elementKey(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// intentList returns the elements of an intent list, a JSON list or comma
+// separated values.
+func intentList(intent string) []string {
+	intent = strings.TrimSpace(intent)
+	if strings.HasPrefix(intent, "[") {
+		var values []interface{}
+		if err := json.Unmarshal([]byte(intent), &values); err == nil {
+			list := make([]string, len(values))
+			for i, v := range values {
+				list[i] = elementKey(v)
+			}
+			return list
+		}
+	}
+	if len(intent) == 0 {
+		return []string{}
+	}
+	list := strings.Split(intent, ",")
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	return list
+}
+
+// numberValue returns a number or numeric string device value.
+func numberValue(v interface{}) (float64, bool) {
+	n, ok := pmNumber(v)
+	return n.ValueFloat64(), ok
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookupPath(t *testing.T) {
+	content := map[string]interface{}{
+		"aid": "XR-L1-C1",
+		"capabilities": map[string]interface{}{
+			"maxBaud": 66.5,
+			"modes":   []interface{}{"a", map[string]interface{}{"name": "b"}},
+		},
+		"empty": nil,
+	}
+	tests := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{"aid", "XR-L1-C1", true},
+		{"capabilities.maxBaud", 66.5, true},
+		{"capabilities.modes.0", "a", true},
+		{"capabilities.modes.1.name", "b", true},
+		{"capabilities.modes.2", nil, false},
+		{"capabilities.modes.-1", nil, false},
+		{"capabilities.modes.x", nil, false},
+		{"capabilities.missing", nil, false},
+		{"aid.more", nil, false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := lookupPath(content, tt.path)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookupPath(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCompareValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		operator  string
+		value     interface{}
+		intent    string
+		tolerance float64
+		want      bool
+		wantErr   bool
+	}{
+		{"default eq string", "", "enabled", "enabled", 0, true, false},
+		{"eq number", operatorEq, 400.0, "400", 0, true, false},
+		{"eq bool", operatorEq, true, "true", 0, true, false},
+		{"eq bool mismatch", operatorEq, false, "true", 0, false, false},
+		{"eq list any order", operatorEq, []interface{}{"b", "a"}, "a, b", 0, true, false},
+		{"ne", operatorNe, "enabled", "disabled", 0, true, false},
+		{"gt", operatorGt, 5.0, "4", 0, true, false},
+		{"gt numeric string", operatorGt, "5", "4", 0, true, false},
+		{"lt", operatorLt, 5.0, "4", 0, false, false},
+		{"gt not a number", operatorGt, "high", "4", 0, false, true},
+		{"gt intent not a number", operatorGt, 5.0, "four", 0, false, true},
+		{"within tolerance", operatorWithin, -3.4, "-3.5", 0.2, true, false},
+		{"within at tolerance", operatorWithin, 10.0, "9", 1, true, false},
+		{"outside tolerance", operatorWithin, -3.0, "-3.5", 0.2, false, false},
+		{"in comma list", operatorIn, "b", "a, b, c", 0, true, false},
+		{"in JSON list", operatorIn, 2.0, "[1, 2, 3]", 0, true, false},
+		{"not in", operatorIn, "d", "a,b,c", 0, false, false},
+		{"regex match", operatorRegex, "XR-L1-C1", "^XR-L[0-9]+", 0, true, false},
+		{"regex no match", operatorRegex, "XR-T1", "^XR-L", 0, false, false},
+		{"regex invalid", operatorRegex, "XR-L1", "XR-(", 0, false, true},
+		{"unknown operator", "like", "a", "a", 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareValue(tt.operator, tt.value, tt.intent, tt.tolerance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compareValue() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("compareValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []interface{}
+		intents []string
+		want    bool
+	}{
+		{"same order", []interface{}{"a", "b"}, []string{"a", "b"}, true},
+		{"any order", []interface{}{"b", "a"}, []string{"a", "b"}, true},
+		{"duplicates", []interface{}{"a", "a", "b"}, []string{"a", "b", "a"}, true},
+		{"duplicate counts differ", []interface{}{"a", "a", "b"}, []string{"a", "b", "b"}, false},
+		{"lengths differ", []interface{}{"a"}, []string{"a", "a"}, false},
+		{"numbers normalized", []interface{}{1.0, 2.5}, []string{"2.50", "1"}, true},
+		{"numbers differ", []interface{}{1.0}, []string{"1.5"}, false},
+		{"bools", []interface{}{true, false}, []string{"false", "true"}, true},
+		{"empty", []interface{}{}, []string{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := equalSet(tt.values, tt.intents); got != tt.want {
+				t.Errorf("equalSet(%v, %v) = %v, want %v", tt.values, tt.intents, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntentList(t *testing.T) {
+	tests := []struct {
+		intent string
+		want   []string
+	}{
+		{"a, b ,c", []string{"a", "b", "c"}},
+		{"a", []string{"a"}},
+		{"", []string{}},
+		{"  ", []string{}},
+		{`["a", "b,c"]`, []string{"a", "b,c"}},
+		{"[1, 2.5, true]", []string{"1", "2.5", "true"}},
+		{"[]", []string{}},
+		{"[a, b]", []string{"[a", "b]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.intent, func(t *testing.T) {
+			if got := intentList(tt.intent); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("intentList(%q) = %q, want %q", tt.intent, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,8 @@ package provider
 import (
 	"context"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -28,8 +29,27 @@ type LCsDataSource struct {
 }
 
 type LCsDataSourceData struct {
-	N   types.String     `tfsdk:"n"`
-	LCs []LCResourceData `tfsdk:"lcs"`
+	N   types.String `tfsdk:"n"`
+	LCs []LCData     `tfsdk:"lcs"`
+}
+
+// LCData is an LC of the data source, the attributes of xrcm_lc the device
+// reports.
+type LCData struct {
+	Id             types.String `tfsdk:"id"`
+	N              types.String `tfsdk:"n"`
+	DeviceId       types.String `tfsdk:"deviceid"`
+	Aid            types.String `tfsdk:"aid"`
+	LcCtrl         types.Int64  `tfsdk:"lcctrl"`
+	LinePTPId      types.String `tfsdk:"lineptpid"`
+	CarrierId      types.String `tfsdk:"carrierid"`
+	Direction      types.String `tfsdk:"direction"`
+	ClientAid      types.String `tfsdk:"clientaid"`
+	LineAid        types.String `tfsdk:"lineaid"`
+	DscgAid        types.String `tfsdk:"dscgaid"`
+	RemoteModuleId types.String `tfsdk:"remotemoduleid"`
+	RemoteClientId types.String `tfsdk:"remoteclientid"`
+	ConfigState    types.String `tfsdk:"configstate"`
 }
 
 // Metadata returns the data source type name.
@@ -135,7 +155,8 @@ func (d *LCsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	tflog.Debug(ctx, "LCsDataSource: get LCS ", map[string]interface{}{"queryData": queryData})
 
-	data, deviceId, err := GetResource(ctx, d.client, queryData.N.ValueString(), "resources/lcs")
+	client := xrcm.NewClientWithRequester(d.client)
+	links, err := client.ListLCs(ctx, queryData.N.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -145,39 +166,39 @@ func (d *LCsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 
-	resultData := (data["data"].(map[string]interface{}))["content"].(map[string]interface{})
-	links := resultData["links"].([]interface{})
 	tflog.Debug(ctx, "LCsDataSource: get LC links", map[string]interface{}{"links": links})
+	deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
 
-	var lcs []LCResourceData
+	var lcs []LCData
 
-	for _, v := range links {
-		lcrec := v.(map[string]interface{})
-		data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources/"+lcrec["href"].(string))
+	for _, href := range links {
+		lc, err := client.GetLC(ctx, queryData.N.ValueString(), href)
 		if err != nil {
-			continue
+			resp.Diagnostics.AddError(
+				"Error Read LCS",
+				"LCsDataSource: Could not GET LC "+href+", unexpected error: "+err.Error(),
+			)
+			return
 		}
 
-		resultData2 := data2["data"].(map[string]interface{})
-		lcDataRec := resultData2["content"].(map[string]interface{})
-		lcData := LCResourceData{}
+		lcData := LCData{}
 		lcData.N = types.StringValue(queryData.N.ValueString())
 		lcData.DeviceId = types.StringValue(deviceId)
-		lcData.Aid = types.StringValue(lcDataRec["aid"].(string))
-		lcData.LcCtrl = types.Int64Value(int64(lcDataRec["lcCtrl"].(float64)))
-		lcData.Id = types.StringValue(queryData.N.ValueString() + lcrec["href"].(string))
-		lcData.ClientAid = types.StringValue(lcDataRec["clientAid"].(string))
-		lcData.LineAid = types.StringValue(lcDataRec["lineAid"].(string))
-		lcData.Direction = types.StringValue(lcDataRec["direction"].(string))
-		lcData.DscgAid = types.StringValue(lcDataRec["dscgAid"].(string))
-		lcData.RemoteModuleId = types.StringValue(lcDataRec["remoteModuleId"].(string))
-		lcData.RemoteClientId = types.StringValue(lcDataRec["remoteClientId"].(string))
-		lcData.ConfigState = types.StringValue(lcDataRec["configState"].(string))
+		lcData.Aid = stringPointerValue(lc.Aid)
+		lcData.LcCtrl = int64PointerValue(lc.LcCtrl)
+		lcData.Id = types.StringValue(queryData.N.ValueString() + href)
+		lcData.ClientAid = stringPointerValue(lc.ClientAid)
+		lcData.LineAid = stringPointerValue(lc.LineAid)
+		lcData.Direction = stringPointerValue(lc.Direction)
+		lcData.DscgAid = stringPointerValue(lc.DscgAid)
+		lcData.RemoteModuleId = stringPointerValue(lc.RemoteModuleId)
+		lcData.RemoteClientId = stringPointerValue(lc.RemoteClientId)
+		lcData.ConfigState = stringPointerValue(lc.ConfigState)
 		lcs = append(lcs, lcData)
 
 	}
 	tflog.Debug(ctx, "LCsDataSource: get LCS", map[string]interface{}{"lcs": lcs})
-	queryData.LCs = make([]LCResourceData, len(lcs))
+	queryData.LCs = make([]LCData, len(lcs))
 	queryData.LCs = lcs
 	diags = resp.State.Set(ctx, &queryData)
 	resp.Diagnostics.Append(diags...)
@@ -4,10 +4,12 @@ import (
 	"context"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -29,14 +31,35 @@ type ACsDataSource struct {
 }
 
 type ModuleACsDataSourceData struct {
-	N          types.String     `tfsdk:"n"`
-	EthernetId types.String     `tfsdk:"ethernetid"`
-	ACIds      []types.String   `tfsdk:"acids"`
-	ACs        []ACResourceData `tfsdk:"acs"`
+	N          types.String   `tfsdk:"n"`
+	EthernetId types.String   `tfsdk:"ethernetid"`
+	ACIds      []types.String `tfsdk:"acids"`
+	ACs        []ACData       `tfsdk:"acs"`
+	Status     types.String   `tfsdk:"status"`
+	Error      types.String   `tfsdk:"error"`
+}
+
+// ACData is an AC of the data source, the attributes of xrcm_ac the device
+// reports.
+type ACData struct {
+	Id          types.String `tfsdk:"id"`
+	N           types.String `tfsdk:"n"`
+	DeviceId    types.String `tfsdk:"deviceid"`
+	EthernetId  types.String `tfsdk:"ethernetid"`
+	AcId        types.String `tfsdk:"acid"`
+	Aid         types.String `tfsdk:"aid"`
+	Capacity    types.Int64  `tfsdk:"capacity"`
+	Imc         types.String `tfsdk:"imc"`
+	ImcOuterVID types.String `tfsdk:"imc_outer_vid"`
+	Emc         types.String `tfsdk:"emc"`
+	EmcOuterVID types.String `tfsdk:"emc_outer_vid"`
+	MaxPktLen   types.Int64  `tfsdk:"maxpktlen"`
+	ConfigState types.String `tfsdk:"configstate"`
 }
 
 type ACsDataSourceData struct {
-	ModuleACs []ModuleACsDataSourceData `tfsdk:"moduleacs"`
+	ModuleACs       []ModuleACsDataSourceData `tfsdk:"moduleacs"`
+	SkipUnavailable types.Bool                `tfsdk:"skip_unavailable"`
 }
 
 // Metadata returns the data source type name.
@@ -48,11 +71,23 @@ func (d *ACsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of Module ACs",
 		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
 			"moduleacs": schema.ListNestedAttribute{
 				Description: "List of module's ACs",
 				Required:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
 						"n": schema.StringAttribute{
 							Description: "Device Name",
 							Required:    true,
@@ -155,65 +190,73 @@ func (d *ACsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	tflog.Debug(ctx, "ACsDataSource: get AC", map[string]interface{}{"queriesData": queriesData})
 
-	var moduleACs []ModuleACsDataSourceData
+	moduleACs := make([]ModuleACsDataSourceData, len(queriesData.ModuleACs))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModuleACs))
 
-	for _, queryData := range queriesData.ModuleACs {
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModuleACs), func(i int) {
+		queryData := queriesData.ModuleACs[i]
 
 		tflog.Debug(ctx, "ACsDataSource: get ACS, request", map[string]interface{}{"queryData": queryData})
 
-		data, deviceId, err := GetResource(ctx, d.client, queryData.N.ValueString(), "resources/ethernets/"+queryData.EthernetId.ValueString()+"/acs")
+		client := xrcm.NewClientWithRequester(d.client)
+		links, err := client.ListACs(ctx, queryData.N.ValueString(), queryData.EthernetId.ValueString())
 
 		if err != nil {
-			resp.Diagnostics.AddError(
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
 				"Error Read ACS",
 				"ACsDataSource: Could not GET ACS, unexpected error: "+err.Error(),
 			)
+			moduleACs[i] = queryData
 			return
 		}
 
-		tflog.Debug(ctx, "ACsDataSource: get ACS", map[string]interface{}{"data": data})
-
-		resultData := data["data"].(map[string]interface{})
-		content := resultData["content"].(map[string]interface{})
-		links := content["links"].([]interface{})
 		tflog.Debug(ctx, "ACsDataSource: get ACS links", map[string]interface{}{"links": links})
-		var acs []ACResourceData
+		deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
+		var acs []ACData
 
-		for _, v := range links {
-			rec := v.(map[string]interface{})
-			href := rec["href"].(string)
+		for _, href := range links {
 			acId := href[strings.LastIndex(href, "/")+1:]
 
 			if len(queryData.ACIds) > 0 && Find(acId, queryData.ACIds) == -1 {
 				continue
 			}
-			data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources/"+href)
+			ac, err := client.GetAC(ctx, queryData.N.ValueString(), href)
 			if err != nil {
-				continue
+				reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+					"Error Read ACS",
+					"ACsDataSource: Could not GET AC "+href+", unexpected error: "+err.Error(),
+				)
+				moduleACs[i] = queryData
+				return
 			}
 
-			resultData2 := data2["data"].(map[string]interface{})
-			acDataRec := resultData2["content"].(map[string]interface{})
-			acData := ACResourceData{}
+			acData := ACData{}
 			acData.N = types.StringValue(queryData.N.ValueString())
 			acData.DeviceId = types.StringValue(deviceId)
-			acData.Aid = types.StringValue(acDataRec["aid"].(string))
+			acData.Aid = stringPointerValue(ac.Aid)
 			acData.Id = types.StringValue(queryData.N.ValueString() + href)
 			acData.AcId = types.StringValue(acId)
-			var x int64 = int64(acDataRec["capacity"].(float64))
-			acData.Capacity = types.Int64Value(x)
-			acData.Imc = types.StringValue(acDataRec["imc"].(string))
-			acData.ImcOuterVID = types.StringValue(acDataRec["imcOuterVID"].(string))
-			acData.Emc = types.StringValue(acDataRec["emc"].(string))
-			acData.EmcOuterVID = types.StringValue(acDataRec["emcOuterVID"].(string))
-			acData.MaxPktLen = types.Int64Value(int64(acDataRec["maxPktLen"].(float64)))
-			acData.ConfigState = types.StringValue(acDataRec["configState"].(string))
+			acData.Capacity = int64PointerValue(ac.Capacity)
+			acData.Imc = stringPointerValue(ac.Imc)
+			acData.ImcOuterVID = stringPointerValue(ac.ImcOuterVID)
+			acData.Emc = stringPointerValue(ac.Emc)
+			acData.EmcOuterVID = stringPointerValue(ac.EmcOuterVID)
+			acData.MaxPktLen = int64PointerValue(ac.MaxPktLen)
+			acData.ConfigState = stringPointerValue(ac.ConfigState)
 			acs = append(acs, acData)
 		}
 		tflog.Debug(ctx, "ACsDataSource: get carriers", map[string]interface{}{"device Name": queryData.N.ValueString(), "ethernetId": queryData.EthernetId.ValueString(), "ACIDs": queryData.ACIds, "module ACs": acs})
-		queryData.ACs = make([]ACResourceData, len(acs))
+		queryData.ACs = make([]ACData, len(acs))
 		queryData.ACs = acs
-		moduleACs = append(moduleACs, queryData)
+		queryData.Status = types.StringValue(moduleStatusOK)
+		moduleACs[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	tflog.Debug(ctx, "ACsDataSource: get module ACs", map[string]interface{}{"Module ACs": moduleACs})
 	queriesData.ModuleACs = make([]ModuleACsDataSourceData, len(moduleACs))
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &AlarmsDataSource{}
+	_ datasource.DataSourceWithConfigure = &AlarmsDataSource{}
+)
+
+// NewAlarmsDataSource is a helper function to simplify the provider implementation.
+func NewAlarmsDataSource() datasource.DataSource {
+	return &AlarmsDataSource{}
+}
+
+// AlarmsDataSource lists the active alarms of modules.
+type AlarmsDataSource struct {
+	client *xrcm_pf.Client
+}
+
+type AlarmData struct {
+	Id            types.String `tfsdk:"id"`
+	Href          types.String `tfsdk:"href"`
+	Aid           types.String `tfsdk:"aid"`
+	ResourceType  types.String `tfsdk:"resourcetype"`
+	Severity      types.String `tfsdk:"severity"`
+	ConditionType types.String `tfsdk:"conditiontype"`
+	RaisedTime    types.String `tfsdk:"raisedtime"`
+	Description   types.String `tfsdk:"description"`
+}
+
+type ModuleAlarmsData struct {
+	N        types.String `tfsdk:"n"`
+	DeviceId types.String `tfsdk:"deviceid"`
+	Alarms   []AlarmData  `tfsdk:"alarms"`
+	Status   types.String `tfsdk:"status"`
+	Error    types.String `tfsdk:"error"`
+}
+
+type AlarmsDataSourceData struct {
+	ModuleAlarms    []ModuleAlarmsData `tfsdk:"modulealarms"`
+	Severities      []types.String     `tfsdk:"severities"`
+	ResourceTypes   []types.String     `tfsdk:"resourcetypes"`
+	MinAge          types.String       `tfsdk:"min_age"`
+	MaxAge          types.String       `tfsdk:"max_age"`
+	SkipUnavailable types.Bool         `tfsdk:"skip_unavailable"`
+}
+
+// Metadata returns the data source type name.
+func (d *AlarmsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alarms"
+}
+
+func (d *AlarmsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the active alarms of Modules",
+		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
+			"severities": schema.ListAttribute{
+				Description: "Only the alarms of these severities, e.g. critical, major, case insensitive",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"resourcetypes": schema.ListAttribute{
+				Description: "Only the alarms raised on these resource types, e.g. xrcm_carrier, xrcm_lc",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"min_age": schema.StringAttribute{
+				Description: "Only the alarms raised at least this long ago, e.g. 5m",
+				Optional:    true,
+			},
+			"max_age": schema.StringAttribute{
+				Description: "Only the alarms raised at most this long ago, e.g. 1h",
+				Optional:    true,
+			},
+			"modulealarms": schema.ListNestedAttribute{
+				Description: "List of modules' alarms",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
+						"n": schema.StringAttribute{
+							Description: "Device Name",
+							Required:    true,
+						},
+						"deviceid": schema.StringAttribute{
+							Description: "device id",
+							Computed:    true,
+						},
+						"alarms": schema.ListNestedAttribute{
+							Description: "List of active alarms",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.StringAttribute{
+										Description: "Identifier of the alarm, device name and href or id of the alarm, else href of the resource raising it and condition type.",
+										Computed:    true,
+									},
+									"href": schema.StringAttribute{
+										Description: "href of the resource raising the alarm",
+										Computed:    true,
+									},
+									"aid": schema.StringAttribute{
+										Description: "aid of the resource raising the alarm",
+										Computed:    true,
+									},
+									"resourcetype": schema.StringAttribute{
+										Description: "resource type of the resource raising the alarm, e.g. xrcm_carrier",
+										Computed:    true,
+									},
+									"severity": schema.StringAttribute{
+										Description: "severity",
+										Computed:    true,
+									},
+									"conditiontype": schema.StringAttribute{
+										Description: "condition type",
+										Computed:    true,
+									},
+									"raisedtime": schema.StringAttribute{
+										Description: "time the alarm was raised",
+										Computed:    true,
+									},
+									"description": schema.StringAttribute{
+										Description: "description",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *AlarmsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*xrcm_pf.Client)
+}
+
+func (d *AlarmsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	queriesData := AlarmsDataSourceData{}
+
+	diags := req.Config.Get(ctx, &queriesData)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	minAge := parseAge(queriesData.MinAge, "min_age", &resp.Diagnostics)
+	maxAge := parseAge(queriesData.MaxAge, "max_age", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "AlarmsDataSource: get Alarms", map[string]interface{}{"queriesData": queriesData})
+
+	now := time.Now()
+	moduleAlarms := make([]ModuleAlarmsData, len(queriesData.ModuleAlarms))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModuleAlarms))
+
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModuleAlarms), func(i int) {
+		queryData := queriesData.ModuleAlarms[i]
+
+		alarms, deviceId, err := getAlarms(ctx, d.client, queryData.N.ValueString())
+		queryData.DeviceId = types.StringValue(deviceId)
+		if err != nil {
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+				"Error Read AlarmsDataSource",
+				"AlarmsDataSource: Could not GET Alarms, unexpected error: "+err.Error(),
+			)
+			moduleAlarms[i] = queryData
+			return
+		}
+
+		queryData.Alarms = make([]AlarmData, 0, len(alarms))
+		for _, alarm := range alarms {
+			if len(queriesData.Severities) > 0 && findFold(alarm.Severity.ValueString(), queriesData.Severities) == -1 {
+				continue
+			}
+			if len(queriesData.ResourceTypes) > 0 && Find(alarm.ResourceType.ValueString(), queriesData.ResourceTypes) == -1 {
+				continue
+			}
+			// Alarms without a known raised time are kept, their age is unknown.
+			if raised, err := time.Parse(time.RFC3339, alarm.RaisedTime.ValueString()); err == nil {
+				age := now.Sub(raised)
+				if (minAge > 0 && age < minAge) || (maxAge > 0 && age > maxAge) {
+					continue
+				}
+			}
+			queryData.Alarms = append(queryData.Alarms, alarm)
+		}
+		queryData.Status = types.StringValue(moduleStatusOK)
+		moduleAlarms[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "AlarmsDataSource: get module alarms", map[string]interface{}{"modulealarms": moduleAlarms})
+	queriesData.ModuleAlarms = moduleAlarms
+	diags = resp.State.Set(ctx, &queriesData)
+	resp.Diagnostics.Append(diags...)
+}
+
+// getAlarms returns the active alarms of the device.
+func getAlarms(ctx context.Context, client *xrcm_pf.Client, deviceName string) ([]AlarmData, string, error) {
+	deviceId, _ := client.GetDeviceIdFromName(deviceName)
+	records, err := xrcm.NewClientWithRequester(client).ListAlarms(ctx, deviceName)
+	if err != nil {
+		return nil, deviceId, err
+	}
+
+	alarms := make([]AlarmData, 0, len(records))
+	for _, rec := range records {
+		alarm := getAlarm(deviceName, rec)
+		alarm.ResourceType = types.StringValue(client.ResourceType(alarm.Href.ValueString()))
+		alarms = append(alarms, alarm)
+	}
+	return alarms, deviceId, nil
+}
+
+// getAlarm returns the alarm of an alarm record of the device.
+func getAlarm(deviceName string, rec xrcm.Alarm) AlarmData {
+	alarm := AlarmData{
+		Href:          stringPointerValue(rec.Resource),
+		Aid:           stringPointerValue(rec.Aid),
+		Severity:      stringPointerValue(rec.Severity),
+		ConditionType: stringPointerValue(rec.ConditionType),
+		RaisedTime:    stringPointerValue(rec.RaisedTime),
+		Description:   stringPointerValue(rec.Description),
+	}
+	alarm.Id = types.StringValue(alarmId(deviceName, rec))
+	return alarm
+}
+
+// alarmId returns the identifier of an alarm: the device name and the href of
+// the alarm, or else its id, or else the resource raising it and the
+// condition, which the device raises once per resource.
+func alarmId(deviceName string, rec xrcm.Alarm) string {
+	switch {
+	case len(rec.Href) > 0:
+		return deviceName + rec.Href
+	case rec.Id != nil && len(*rec.Id) > 0:
+		return deviceName + "/alarms/" + *rec.Id
+	}
+	resource, condition := "", ""
+	if rec.Resource != nil {
+		resource = *rec.Resource
+	}
+	if rec.ConditionType != nil {
+		condition = *rec.ConditionType
+	}
+	return deviceName + resource + "#" + condition
+}
+
+// parseAge returns the duration of an age filter, zero if it is not set.
+func parseAge(v types.String, attribute string, diags *diag.Diagnostics) time.Duration {
+	if v.IsNull() || v.IsUnknown() {
+		return 0
+	}
+	age, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"AlarmsDataSource: Invalid Age",
+			"Read: Could not parse "+attribute+", unexpected error: "+err.Error(),
+		)
+	}
+	return age
+}
+
+// findFold is Find ignoring case.
+func findFold(what string, where []types.String) int {
+	for i, v := range where {
+		if strings.EqualFold(v.ValueString(), what) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
+)
+
+func TestAlarmId(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  xrcm.Alarm
+		want string
+	}{
+		{"own href", xrcm.Alarm{Href: "/alarms/7", Id: xrcm.String("7")}, "xr-hub/alarms/7"},
+		{"id", xrcm.Alarm{Id: xrcm.String("7"), Resource: xrcm.String("/lineptps/1")}, "xr-hub/alarms/7"},
+		{"resource and condition", xrcm.Alarm{Resource: xrcm.String("/lineptps/1"), ConditionType: xrcm.String("LOS")}, "xr-hub/lineptps/1#LOS"},
+		{"empty id", xrcm.Alarm{Id: xrcm.String(""), Resource: xrcm.String("/lineptps/1"), ConditionType: xrcm.String("LOF")}, "xr-hub/lineptps/1#LOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alarmId("xr-hub", tt.rec); got != tt.want {
+				t.Errorf("alarmId() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Two conditions of one resource without ids must not collide.
+	los := alarmId("xr-hub", xrcm.Alarm{Resource: xrcm.String("/lineptps/1"), ConditionType: xrcm.String("LOS")})
+	lof := alarmId("xr-hub", xrcm.Alarm{Resource: xrcm.String("/lineptps/1"), ConditionType: xrcm.String("LOF")})
+	if los == lof {
+		t.Errorf("alarmId() of two conditions of a resource = %q for both", los)
+	}
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &CarrierPMDataSource{}
+	_ datasource.DataSourceWithConfigure = &CarrierPMDataSource{}
+)
+
+// NewCarrierPMDataSource is a helper function to simplify the provider implementation.
+func NewCarrierPMDataSource() datasource.DataSource {
+	return &CarrierPMDataSource{}
+}
+
+// CarrierPMDataSource reads the current PM of the carriers.
+type CarrierPMDataSource struct {
+	client *xrcm_pf.Client
+}
+
+type CarrierPMData struct {
+	Id        types.String           `tfsdk:"id"`
+	CarrierId types.String           `tfsdk:"carrierid"`
+	Metrics   map[string]PMValueData `tfsdk:"metrics"`
+}
+
+type CarriersPMData struct {
+	N          types.String    `tfsdk:"n"`
+	DeviceId   types.String    `tfsdk:"deviceid"`
+	LinePTPId  types.String    `tfsdk:"lineptpid"`
+	CarrierIds []types.String  `tfsdk:"carrierids"`
+	PMs        []CarrierPMData `tfsdk:"pms"`
+	Status     types.String    `tfsdk:"status"`
+	Error      types.String    `tfsdk:"error"`
+}
+
+type CarrierPMDataSourceData struct {
+	ModulePMs       []CarriersPMData `tfsdk:"modulepms"`
+	SkipUnavailable types.Bool       `tfsdk:"skip_unavailable"`
+}
+
+// Metadata returns the data source type name.
+func (d *CarrierPMDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_carrier_pm"
+}
+
+func (d *CarrierPMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the current performance monitoring of Modules' carriers",
+		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
+			"modulepms": schema.ListNestedAttribute{
+				Description: "List of modules' carriers PM",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
+						"n": schema.StringAttribute{
+							Description: "Device Name",
+							Required:    true,
+						},
+						"deviceid": schema.StringAttribute{
+							Description: "device id",
+							Computed:    true,
+						},
+						"lineptpid": schema.StringAttribute{
+							Description: "line ptp id",
+							Required:    true,
+						},
+						"carrierids": schema.ListAttribute{
+							Description: "List of carrier ID, default all the carriers of the line ptp",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"pms": schema.ListNestedAttribute{
+							Description: "List of carriers PM",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.StringAttribute{
+										Description: "Identifier of the Carrier, device name and href.",
+										Computed:    true,
+									},
+									"carrierid": schema.StringAttribute{
+										Description: "carrier id",
+										Computed:    true,
+									},
+									"metrics": pmMetricsAttribute("PM values by name as reported by the module, e.g. preFecBer, qFactor, osnr, rxPower, txPower, fecCorrected, fecUncorrected"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *CarrierPMDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*xrcm_pf.Client)
+}
+
+func (d *CarrierPMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	queriesData := CarrierPMDataSourceData{}
+
+	diags := req.Config.Get(ctx, &queriesData)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "CarrierPMDataSource: get Carriers PM", map[string]interface{}{"queriesData": queriesData})
+
+	modulePMs := make([]CarriersPMData, len(queriesData.ModulePMs))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModulePMs))
+
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModulePMs), func(i int) {
+		queryData := queriesData.ModulePMs[i]
+
+		pms, deviceId, err := readPMs(ctx, d.client, queryData.N.ValueString(), "xr.carrier", "/lineptps/"+queryData.LinePTPId.ValueString()+"/carriers/", queryData.CarrierIds)
+		queryData.DeviceId = types.StringValue(deviceId)
+		if err != nil {
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+				"Error Read CarrierPMDataSource",
+				"CarrierPMDataSource: Could not GET Carriers PM, unexpected error: "+err.Error(),
+			)
+			modulePMs[i] = queryData
+			return
+		}
+
+		queryData.PMs = make([]CarrierPMData, 0, len(pms))
+		for _, p := range pms {
+			queryData.PMs = append(queryData.PMs, CarrierPMData{
+				Id:        types.StringValue(queryData.N.ValueString() + p.href),
+				CarrierId: types.StringValue(p.resourceId),
+				Metrics:   p.metrics,
+			})
+		}
+		queryData.Status = types.StringValue(moduleStatusOK)
+		modulePMs[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "CarrierPMDataSource: get module PMs", map[string]interface{}{"modulepms": modulePMs})
+	queriesData.ModulePMs = modulePMs
+	diags = resp.State.Set(ctx, &queriesData)
+	resp.Diagnostics.Append(diags...)
+}
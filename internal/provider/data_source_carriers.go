@@ -4,12 +4,12 @@ import (
 	"context"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
-	//"github.com/hashicorp/terraform-plugin-framework/attr"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -31,14 +31,58 @@ type CarriersDataSource struct {
 }
 
 type CarriersResourceData struct {
-	N          types.String          `tfsdk:"n"`
-	LinePTPId  types.String          `tfsdk:"lineptpid"`
-	CarrierIds []types.String        `tfsdk:"carrierids"`
-	Carriers   []CarrierResourceData `tfsdk:"carriers"`
+	N          types.String   `tfsdk:"n"`
+	LinePTPId  types.String   `tfsdk:"lineptpid"`
+	CarrierIds []types.String `tfsdk:"carrierids"`
+	Carriers   []CarrierData  `tfsdk:"carriers"`
+	Status     types.String   `tfsdk:"status"`
+	Error      types.String   `tfsdk:"error"`
+}
+
+// CarrierData is a carrier of the data source, the attributes of xrcm_carrier
+// the device reports.
+type CarrierData struct {
+	Id                      types.String `tfsdk:"id"`
+	N                       types.String `tfsdk:"n"`
+	DeviceId                types.String `tfsdk:"deviceid"`
+	LinePTPId               types.String `tfsdk:"lineptpid"`
+	CarrierId               types.String `tfsdk:"carrierid"`
+	Aid                     types.String `tfsdk:"aid"`
+	Modulation              types.String `tfsdk:"modulation"`
+	HModulation             types.String `tfsdk:"hmodulation"`
+	OModulation             types.String `tfsdk:"omodulation"`
+	ClientPortMode          types.String `tfsdk:"clientportmode"`
+	FecIterations           types.String `tfsdk:"feciterations"`
+	HFecIterations          types.String `tfsdk:"hfeciterations"`
+	OFecIterations          types.String `tfsdk:"ofeciterations"`
+	ConstellationFrequency  types.Int64  `tfsdk:"constellationfrequency"`
+	HFrequency              types.Int64  `tfsdk:"hfrequency"`
+	AConstellationFrequency types.Int64  `tfsdk:"aconstellationfrequency"`
+	OperatingFrequency      types.Int64  `tfsdk:"operatingfrequency"`
+	BaudRate                types.Int64  `tfsdk:"baudrate"`
+	TxCLPtarget             types.Int64  `tfsdk:"txclptarget"`
+	HTxCLPtarget            types.Int64  `tfsdk:"htxclptarget"`
+	ATxCLPtarget            types.Int64  `tfsdk:"atxclptarget"`
+	MaxDSCs                 types.Int64  `tfsdk:"maxdscs"`
+	HMaxDSCs                types.Int64  `tfsdk:"hmaxdscs"`
+	OMaxDSCs                types.Int64  `tfsdk:"omaxdscs"`
+	MaxTxDSCs               types.Int64  `tfsdk:"maxtxdscs"`
+	HMaxTxDSCs              types.Int64  `tfsdk:"hmaxtxdscs"`
+	OMaxTxDSCs              types.Int64  `tfsdk:"omaxtxdscs"`
+	AdvLineCtrl             types.String `tfsdk:"advlinectrl"`
+	SpectralBandwidth       types.Int64  `tfsdk:"spectralbandwidth"`
+	AllowedTxCDSCs          types.Int64  `tfsdk:"allowedtxcdscs"`
+	HAllowedTxCDSCs         types.Int64  `tfsdk:"hallowedtxcdscs"`
+	AAllowedTxCDSCs         types.Int64  `tfsdk:"aallowedtxcdscs"`
+	AllowedRxCDSCs          types.Int64  `tfsdk:"allowedrxcdscs"`
+	HAllowedRxCDSCs         types.Int64  `tfsdk:"hallowedrxcdscs"`
+	AAllowedRxCDSCs         types.Int64  `tfsdk:"aallowedrxcdscs"`
+	Capabilities            types.Map    `tfsdk:"capabilities"`
 }
 
 type CarriersDataSourceData struct {
-	ModuleCarriers []CarriersResourceData `tfsdk:"modulecarriers"`
+	ModuleCarriers  []CarriersResourceData `tfsdk:"modulecarriers"`
+	SkipUnavailable types.Bool             `tfsdk:"skip_unavailable"`
 }
 
 // Metadata returns the data source type name.
@@ -50,11 +94,23 @@ func (d *CarriersDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of Modules' carries information",
 		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
 			"modulecarriers": schema.ListNestedAttribute{
 				Description: "List of module's modules' carriers",
 				Required:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
 						"n": schema.StringAttribute{
 							Description: "Device Name",
 							Required:    true,
@@ -250,34 +306,35 @@ func (d *CarriersDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 	tflog.Debug(ctx, "ModuleCarriersDataSource: get Carriers", map[string]interface{}{"queriesData": queriesData})
 
-	var modulecarriers []CarriersResourceData
+	modulecarriers := make([]CarriersResourceData, len(queriesData.ModuleCarriers))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModuleCarriers))
 
-	for _, queryData := range queriesData.ModuleCarriers {
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModuleCarriers), func(i int) {
+		queryData := queriesData.ModuleCarriers[i]
 
 		tflog.Debug(ctx, "ModuleCarriersDataSource: get Carriers", map[string]interface{}{"queryData": queryData})
 
-		data, deviceId, err := GetResource(ctx, d.client, queryData.N.ValueString(), "resource-links")
+		client := xrcm.NewClientWithRequester(d.client)
+		links, err := client.ResourceLinks(ctx, queryData.N.ValueString())
 
 		if err != nil {
-			resp.Diagnostics.AddError(
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
 				"Error Read ModuleCarriersDataSource",
 				"ModuleCarriersDataSource: Could not GET Carriers, unexpected error: "+err.Error(),
 			)
+			modulecarriers[i] = queryData
 			return
 		}
 
-		tflog.Debug(ctx, "ModuleCarriersDataSource: get Carriers", map[string]interface{}{"data": data})
+		tflog.Debug(ctx, "ModuleCarriersDataSource: get Carriers", map[string]interface{}{"links": links})
+		deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
 
-		resources := data["resources"].([]interface{})
+		var carriers []CarrierData
 
-		var carriers []CarrierResourceData
+		for _, link := range links {
+			href := link.Href
 
-		for _, v := range resources {
-			rec := v.(map[string]interface{})
-			rsType := rec["resourceTypes"].([]interface{})
-			href := rec["href"].(string)
-
-			if rsType[0].(string) != "xr.carrier" || !strings.Contains(href, "lineptps/"+queryData.LinePTPId.ValueString()) {
+			if len(link.ResourceTypes) == 0 || link.ResourceTypes[0] != "xr.carrier" || !strings.Contains(href, "lineptps/"+queryData.LinePTPId.ValueString()) {
 				continue
 			}
 			carrierId := href[strings.LastIndex(href, "/")+1:]
@@ -286,60 +343,67 @@ func (d *CarriersDataSource) Read(ctx context.Context, req datasource.ReadReques
 				continue
 			}
 
-			data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources"+rec["href"].(string))
+			carrier, err := client.GetCarrier(ctx, queryData.N.ValueString(), queryData.LinePTPId.ValueString(), carrierId)
 			if err != nil {
-				continue
+				reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+					"Error Read ModuleCarriersDataSource",
+					"ModuleCarriersDataSource: Could not GET carrier "+href+", unexpected error: "+err.Error(),
+				)
+				modulecarriers[i] = queryData
+				return
 			}
 
-			resultData2 := data2["data"].(map[string]interface{})
-			carrier := resultData2["content"].(map[string]interface{})
-			carrierData := CarrierResourceData{}
+			carrierData := CarrierData{}
 			carrierData.N = queryData.N
 			carrierData.DeviceId = types.StringValue(deviceId)
 			carrierData.LinePTPId = queryData.LinePTPId
 			carrierData.CarrierId = types.StringValue(carrierId)
-			carrierData.Aid = types.StringValue(carrier["aid"].(string))
+			carrierData.Aid = stringPointerValue(carrier.Aid)
 			carrierData.Id = types.StringValue(queryData.N.ValueString() + href)
-			carrierData.FecIterations = types.StringValue(carrier["fecIterations"].(string))
-			carrierData.AdvLineCtrl = types.StringValue(carrier["advLineCtrl"].(string))
-			carrierData.Modulation = types.StringValue(carrier["modulation"].(string))
-			carrierData.ClientPortMode = types.StringValue(carrier["clientPortMode"].(string))
-			carrierData.ConstellationFrequency = types.Int64Value(int64(carrier["constellationFrequency"].(float64)))
-			carrierData.BaudRate = types.Int64Value(int64(carrier["baudRate"].(float64)))
-			carrierData.MaxDSCs = types.Int64Value(int64(carrier["maxDSCs"].(float64)))
-			carrierData.MaxTxDSCs = types.Int64Value(int64(carrier["maxTxDSCs"].(float64)))
-			carrierData.SpectralBandwidth = types.Int64Value(int64(carrier["spectralBandwidth"].(float64)))
-			carrierData.TxCLPtarget = types.Int64Value(int64(carrier["txCLPtarget"].(float64)))
-			carrierData.AllowedTxCDSCs = types.Int64Value(int64(carrier["allowedTxCDSCs"].(float64)))
-			carrierData.AllowedRxCDSCs = types.Int64Value(int64(carrier["allowedRxCDSCs"].(float64)))
-			carrierData.HModulation = types.StringValue(carrier["hModulation"].(string))
-			carrierData.OModulation = types.StringValue(carrier["oModulation"].(string))
-			carrierData.HFecIterations = types.StringValue(carrier["hFecIterations"].(string))
-			carrierData.OFecIterations = types.StringValue(carrier["oFecIterations"].(string))
-			carrierData.HFrequency = types.Int64Value(int64(carrier["hFrequency"].(float64)))
-			carrierData.AConstellationFrequency = types.Int64Value(int64(carrier["aConstellationFrequency"].(float64)))
-			carrierData.OperatingFrequency = types.Int64Value(int64(carrier["operatingFrequency"].(float64)))
-			carrierData.HTxCLPtarget = types.Int64Value(int64(carrier["hTxCLPtarget"].(float64)))
-			carrierData.ATxCLPtarget = types.Int64Value(int64(carrier["aTxCLPtarget"].(float64)))
-			carrierData.OMaxDSCs = types.Int64Value(int64(carrier["oMaxDSCs"].(float64)))
-			carrierData.HMaxDSCs = types.Int64Value(int64(carrier["hMaxDSCs"].(float64)))
-			carrierData.HMaxTxDSCs = types.Int64Value(int64(carrier["hMaxTxDSCs"].(float64)))
-			carrierData.OMaxTxDSCs = types.Int64Value(int64(carrier["oMaxTxDSCs"].(float64)))
-			carrierData.HAllowedTxCDSCs = types.Int64Value(int64(carrier["hAllowedTxCDSCs"].(float64)))
-			carrierData.AAllowedTxCDSCs = types.Int64Value(int64(carrier["aAllowedTxCDSCs"].(float64)))
-			carrierData.HAllowedRxCDSCs = types.Int64Value(int64(carrier["hAllowedRxCDSCs"].(float64)))
-			carrierData.AAllowedRxCDSCs = types.Int64Value(int64(carrier["aAllowedRxCDSCs"].(float64)))
-			capMap := make(map[string]attr.Value)
-			for k,v2 := range carrier["capabilities"].(map[string]interface{})  {
-				capMap[k] = types.StringValue(v2.(string))
-			}
-			carrierData.Capabilities, _ = types.MapValue(types.StringType, capMap)
+			carrierData.FecIterations = stringPointerValue(carrier.FecIterations)
+			carrierData.AdvLineCtrl = stringPointerValue(carrier.AdvLineCtrl)
+			carrierData.Modulation = stringPointerValue(carrier.Modulation)
+			carrierData.ClientPortMode = stringPointerValue(carrier.ClientPortMode)
+			carrierData.ConstellationFrequency = int64PointerValue(carrier.ConstellationFrequency)
+			carrierData.BaudRate = int64PointerValue(carrier.BaudRate)
+			carrierData.MaxDSCs = int64PointerValue(carrier.MaxDSCs)
+			carrierData.MaxTxDSCs = int64PointerValue(carrier.MaxTxDSCs)
+			carrierData.SpectralBandwidth = int64PointerValue(carrier.SpectralBandwidth)
+			carrierData.TxCLPtarget = int64PointerValue(carrier.TxCLPtarget)
+			carrierData.AllowedTxCDSCs = int64PointerValue(carrier.AllowedTxCDSCs)
+			carrierData.AllowedRxCDSCs = int64PointerValue(carrier.AllowedRxCDSCs)
+			carrierData.HModulation = stringPointerValue(carrier.HModulation)
+			carrierData.OModulation = stringPointerValue(carrier.OModulation)
+			carrierData.HFecIterations = stringPointerValue(carrier.HFecIterations)
+			carrierData.OFecIterations = stringPointerValue(carrier.OFecIterations)
+			carrierData.HFrequency = int64PointerValue(carrier.HFrequency)
+			carrierData.AConstellationFrequency = int64PointerValue(carrier.AConstellationFrequency)
+			carrierData.OperatingFrequency = int64PointerValue(carrier.OperatingFrequency)
+			carrierData.HTxCLPtarget = int64PointerValue(carrier.HTxCLPtarget)
+			carrierData.ATxCLPtarget = int64PointerValue(carrier.ATxCLPtarget)
+			carrierData.OMaxDSCs = int64PointerValue(carrier.OMaxDSCs)
+			carrierData.HMaxDSCs = int64PointerValue(carrier.HMaxDSCs)
+			carrierData.HMaxTxDSCs = int64PointerValue(carrier.HMaxTxDSCs)
+			carrierData.OMaxTxDSCs = int64PointerValue(carrier.OMaxTxDSCs)
+			carrierData.HAllowedTxCDSCs = int64PointerValue(carrier.HAllowedTxCDSCs)
+			carrierData.AAllowedTxCDSCs = int64PointerValue(carrier.AAllowedTxCDSCs)
+			carrierData.HAllowedRxCDSCs = int64PointerValue(carrier.HAllowedRxCDSCs)
+			carrierData.AAllowedRxCDSCs = int64PointerValue(carrier.AAllowedRxCDSCs)
+			carrierData.Capabilities = capabilitiesValue(carrier.Capabilities)
 			carriers = append(carriers, carrierData)
 		}
 		tflog.Debug(ctx, "ModuleCarriersDataSource: get carriers", map[string]interface{}{"N": queryData.N.ValueString, "LinePTP": queryData.LinePTPId.ValueString, "carriers": queryData.Carriers, "module carriers": carriers})
-		queryData.Carriers = make([]CarrierResourceData, len(carriers))
+		queryData.Carriers = make([]CarrierData, len(carriers))
 		queryData.Carriers = carriers
-		modulecarriers = append(modulecarriers, queryData)
+		queryData.Status = types.StringValue(moduleStatusOK)
+		modulecarriers[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	tflog.Debug(ctx, "ModuleCarriersDataSource: get module carriers", map[string]interface{}{"carriers": modulecarriers})
@@ -3,7 +3,7 @@ package provider
 import (
 	"context"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -31,6 +31,8 @@ type CheckDataSourceData struct {
 	Condition   types.Bool   `tfsdk:"condition"`
 	Description types.String `tfsdk:"description"`
 	Throw       types.String `tfsdk:"throw"`
+	Severity    types.String `tfsdk:"severity"`
+	Passed      types.Bool   `tfsdk:"passed"`
 }
 
 // Metadata returns the data source type name.
@@ -54,6 +56,11 @@ func (d CheckDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				Description: "throw",
 				Optional:    true,
 			},
+			"severity": checkSeverityAttribute(),
+			"passed": schema.BoolAttribute{
+				Description: "The condition is false",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -80,7 +87,22 @@ func (d *CheckDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	tflog.Debug(ctx, "CheckDataSource: get Check, request", map[string]interface{}{"checkData": checkData})
 
+	severity := checkSeverity(checkData.Severity, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	checkData.Passed = types.BoolValue(!checkData.Condition.ValueBool())
+	diags = resp.State.Set(ctx, &checkData)
+	resp.Diagnostics.Append(diags...)
+
 	if checkData.Condition.ValueBool() {
+		if severity == checkSeverityWarning {
+			resp.Diagnostics.AddWarning(
+				"Check Condition Failed!! << "+checkData.Description.ValueString()+" >>",
+				"Warning: "+checkData.Throw.ValueString(),
+			)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Check Condition Failed!! << "+checkData.Description.ValueString()+" >>",
 			"Error: "+checkData.Throw.ValueString(),
@@ -5,15 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"reflect"
-	"strconv"
 	"strings"
+	"time"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -35,16 +35,19 @@ type CheckResourcesDataSource struct {
 }
 
 type AttributeValuesData struct {
-	Attribute              types.String `tfsdk:"attribute"`
-	IntentValue            types.String `tfsdk:"intentvalue"`
-	DeviceValue            types.String `tfsdk:"devicevalue"`
-	ControlAttribute       types.String `tfsdk:"controlattribute"`
-	IsValueMatch           types.Bool   `tfsdk:"isvaluematch"`
-	AttributeControlByHost types.Bool   `tfsdk:"attributecontrolbyhost"`
+	Attribute              types.String  `tfsdk:"attribute"`
+	IntentValue            types.String  `tfsdk:"intentvalue"`
+	Operator               types.String  `tfsdk:"operator"`
+	Tolerance              types.Float64 `tfsdk:"tolerance"`
+	DeviceValue            types.String  `tfsdk:"devicevalue"`
+	ControlAttribute       types.String  `tfsdk:"controlattribute"`
+	IsValueMatch           types.Bool    `tfsdk:"isvaluematch"`
+	AttributeControlByHost types.Bool    `tfsdk:"attributecontrolbyhost"`
 }
 
 type ResourceData struct {
 	Id              types.String          `tfsdk:"id"`
+	Href            types.String          `tfsdk:"href"`
 	GrandparentId   types.String          `tfsdk:"grandparentid"`
 	ParentId        types.String          `tfsdk:"parentid"`
 	ResourceId      types.String          `tfsdk:"resourceid"`
@@ -60,7 +63,12 @@ type ResourceDataSourceData struct {
 }
 
 type ResourcesDataSourceData struct {
-	Queries []ResourceDataSourceData `tfsdk:"queries"`
+	Queries        []ResourceDataSourceData `tfsdk:"queries"`
+	WaitUntilMatch types.Bool               `tfsdk:"wait_until_match"`
+	Timeout        types.String             `tfsdk:"timeout"`
+	PollInterval   types.String             `tfsdk:"poll_interval"`
+	ReportPath     types.String             `tfsdk:"report_path"`
+	ReportFormat   types.String             `tfsdk:"report_format"`
 }
 
 // Metadata returns the data source type name.
@@ -71,7 +79,19 @@ func (d *CheckResourcesDataSource) Metadata(_ context.Context, req datasource.Me
 func (d CheckResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Check Resources",
-		Attributes: map[string]schema.Attribute{
+		Attributes: reportAttributes(map[string]schema.Attribute{
+			"wait_until_match": schema.BoolAttribute{
+				Description: "Query the resources again until every attribute value matches or timeout expires, e.g. while the module converges after an apply",
+				Optional:    true,
+			},
+			"timeout": schema.StringAttribute{
+				Description: "How long wait_until_match waits, default 5m",
+				Optional:    true,
+			},
+			"poll_interval": schema.StringAttribute{
+				Description: "How long wait_until_match waits between queries, default 10s",
+				Optional:    true,
+			},
 			"queries": schema.ListNestedAttribute{
 				Description: "List of resources to check",
 				Required:    true,
@@ -86,8 +106,8 @@ func (d CheckResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 							Computed:    true,
 						},
 						"resourcetype": schema.StringAttribute{
-							Description: "resource type",
-							Required:    true,
+							Description: "resource type, Carrier, DSC, DSCG, Ethernet, AC, LC, Config, Device, OTU, ODU, LinePTP, CarrierDiag, DSCDiag, EthernetDiag, OTUDiag, LLDP, HostNeighbors, LineNeighbors or the name of a provider resource e.g. xrcm_carrier, not needed when the resources have an href",
+							Optional:    true,
 						},
 						"resources": schema.ListNestedAttribute{
 							Description: "List of resources",
@@ -102,6 +122,10 @@ func (d CheckResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 										Description: "resource aid",
 										Computed:    true,
 									},
+									"href": schema.StringAttribute{
+										Description: "href of the resource, e.g. /lineptps/1/carriers/1, instead of the resource type and ids",
+										Optional:    true,
+									},
 									"parentid": schema.StringAttribute{
 										Description: "parent resource identifier",
 										Optional:    true,
@@ -123,7 +147,7 @@ func (d CheckResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 										NestedObject: schema.NestedAttributeObject{
 											Attributes: map[string]schema.Attribute{
 												"attribute": schema.StringAttribute{
-													Description: "attribute",
+													Description: "attribute, or dot separated path of a nested attribute, e.g. capabilities.maxBaud, txCDSCs.0",
 													Optional:    true,
 													Required:    false,
 												},
@@ -132,21 +156,29 @@ func (d CheckResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 													Optional:    true,
 													Required:    false,
 												},
+												"operator": schema.StringAttribute{
+													Description: "eq (default), ne, gt, lt, in, regex or within. Lists compare as sets, in any order; the intent value of a list or of in is a JSON list or comma separated values",
+													Optional:    true,
+												},
+												"tolerance": schema.Float64Attribute{
+													Description: "Largest difference between the device and intent values within matches",
+													Optional:    true,
+												},
 												"devicevalue": schema.StringAttribute{
 													Description: "Device value",
 													Computed:    true,
 												},
 												"controlattribute": schema.StringAttribute{
-													Description: "control attribute",
+													Description: "attribute of the device saying whether the host controls the attribute, default the attribute followed by Ctrl or Control",
 													Optional:    true,
 													Required:    false,
 												},
 												"isvaluematch": schema.BoolAttribute{
-													Description: "is value match",
+													Description: "Whether the device value matches the intent value, false when the resource or the attribute is not found.",
 													Computed:    true,
 												},
 												"attributecontrolbyhost": schema.BoolAttribute{
-													Description: "attribute control by host",
+													Description: "The host controls the attribute, as the control attribute says, e.g. modulationCtrl is host",
 													Computed:    true,
 												},
 											},
@@ -158,7 +190,7 @@ func (d CheckResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 					},
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -184,6 +216,85 @@ func (d *CheckResourcesDataSource) Read(ctx context.Context, req datasource.Read
 
 	tflog.Debug(ctx, "CheckResources: get Resources'Queries", map[string]interface{}{"querysData": resourcesDataSourceData.Queries})
 
+	resourcesDataSourceData = checkUntilMatch(ctx, d.client, resourcesDataSourceData, func(data *ResourcesDataSourceData) diag.Diagnostics {
+		return req.Config.Get(ctx, data)
+	}, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "CheckResources: Check Resources", map[string]interface{}{"resourcesDataSourceData": resourcesDataSourceData})
+	diags = resp.State.Set(ctx, &resourcesDataSourceData)
+	resp.Diagnostics.Append(diags...)
+
+	if err := checkResourcesReport(resourcesDataSourceData).write(resourcesDataSourceData.ReportPath, resourcesDataSourceData.ReportFormat); err != nil {
+		resp.Diagnostics.AddError(
+			"Error Read CheckResources",
+			"CheckResources: Could not write report "+resourcesDataSourceData.ReportPath.ValueString()+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// checkClient is the part of the client the check reads the resources with.
+// The resources are read past the response cache, so that wait_until_match
+// sees the device change.
+type checkClient interface {
+	ResolveDevice(devicename string) (string, error)
+	ExecuteHttpCommand(command, commanduri string, commandBody []byte) ([]byte, error)
+	ExecuteDeviceHttpCommandNoCache(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error)
+}
+
+// checkUntilMatch checks the resources of data. With wait_until_match, it
+// queries again from the configuration, read by getConfig, until every
+// attribute value matches or the timeout expires.
+func checkUntilMatch(ctx context.Context, client checkClient, data ResourcesDataSourceData, getConfig func(*ResourcesDataSourceData) diag.Diagnostics, diags *diag.Diagnostics) ResourcesDataSourceData {
+	waitTimeout, pollInterval := checkWait(data, diags)
+	if diags.HasError() {
+		return data
+	}
+	deadline := time.Now().Add(waitTimeout)
+
+	for {
+		checkQueries(ctx, client, &data, diags)
+		if diags.HasError() {
+			return data
+		}
+		mismatches := checkMismatches(data)
+		if len(mismatches) == 0 || !data.WaitUntilMatch.ValueBool() {
+			return data
+		}
+		if !time.Now().Add(pollInterval).Before(deadline) {
+			diags.AddWarning(
+				"CheckResources: Intent Not Matched",
+				"CheckResources: Attribute values still not matching after "+waitTimeout.String()+":\n"+strings.Join(mismatches, "\n"),
+			)
+			return data
+		}
+
+		tflog.Info(ctx, "CheckResources: waiting for intent to match", map[string]interface{}{"mismatches": mismatches, "poll_interval": pollInterval.String()})
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			diags.AddError(
+				"Error Read CheckResources",
+				"CheckResources: Cancelled while waiting for intent to match: "+ctx.Err().Error(),
+			)
+			return data
+		case <-timer.C:
+		}
+
+		data = ResourcesDataSourceData{}
+		diags.Append(getConfig(&data)...)
+		if diags.HasError() {
+			return data
+		}
+	}
+}
+
+// checkQueries reads the resources of the queries and compares their
+// attribute values with the intent values.
+func checkQueries(ctx context.Context, client checkClient, resourcesDataSourceData *ResourcesDataSourceData, diags *diag.Diagnostics) {
 	for index1, deviceQuery := range resourcesDataSourceData.Queries {
 
 		tflog.Debug(ctx, "CheckResources: get Resources for Device", map[string]interface{}{"Device": deviceQuery.N.ValueString(), "Intent Resources": deviceQuery.Resources})
@@ -194,33 +305,10 @@ func (d *CheckResourcesDataSource) Read(ctx context.Context, req datasource.Read
 			}
 			var data2 = map[string]interface{}{}
 			var deviceId string
-			var err = errors.New("")
-			switch deviceQuery.ResourceType.ValueString() {
-			case "Carrier":
-				if !intentResource.ParentId.IsNull() {
-					data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/lineptps/"+intentResource.ParentId.ValueString()+"/carriers/"+intentResource.ResourceId.ValueString())
-				}
-			case "DSC":
-				if !intentResource.ParentId.IsNull() || !intentResource.GrandparentId.IsNull() {
-					data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/lineptps/"+intentResource.GrandparentId.ValueString()+"/carriers/"+intentResource.ParentId.ValueString()+"/dscs/"+intentResource.ResourceId.ValueString())
-				}
-			case "DSCG":
-				if !intentResource.ParentId.IsNull() || !intentResource.GrandparentId.IsNull() {
-					data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/lineptps/"+intentResource.GrandparentId.ValueString()+"/carriers/"+intentResource.ParentId.ValueString()+"/dscgs/"+intentResource.ResourceId.ValueString())
-				}
-			case "Ethernet":
-				data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/ethernets/"+intentResource.ResourceId.ValueString())
-			case "AC":
-				if !intentResource.ParentId.IsNull() {
-					data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/ethernets/"+intentResource.ParentId.ValueString()+"/acs/"+intentResource.ResourceId.ValueString())
-				}
-			case "LC":
-				data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/lcs/"+intentResource.ResourceId.ValueString())
-			case "Config":
-				data2, deviceId, err = GetResource(ctx, d.client, deviceQuery.N.ValueString(), "resources/cfg")
-			case "Device":
-				deviceId, found := d.client.GetDeviceIdFromName(deviceQuery.N.ValueString())
-				if !found {
+			var err error
+			if deviceQuery.ResourceType.ValueString() == "Device" && intentResource.Href.IsNull() {
+				deviceId, err = client.ResolveDevice(deviceQuery.N.ValueString())
+				if err != nil {
 					diags.AddError(
 						"Error CheckResources",
 						"CheckResources: Could not GET Device ID: "+deviceQuery.N.ValueString()+", error = "+err.Error(),
@@ -228,31 +316,46 @@ func (d *CheckResourcesDataSource) Read(ctx context.Context, req datasource.Read
 					return
 				}
 
-				//data2, deviceId, err = GetResource(ctx, d.provider.client, deviceQuery.N.Value, "devices/"+deviceId)
-				body, error := d.client.ExecuteHttpCommand("GET", "devices/"+deviceId, nil)
-				if error != nil {
+				var body []byte
+				body, err = client.ExecuteHttpCommand("GET", "devices/"+deviceId, nil)
+				if err != nil {
 					diags.AddError(
 						"Error CheckResources",
 						"CheckResources: Could not GET Device: "+deviceQuery.N.ValueString()+", error = "+err.Error(),
 					)
 					return
 				}
-				data2 = make(map[string]interface{})
 				err = json.Unmarshal(body, &data2)
-			default:
-				diags.AddError(
-					"Error Read CheckResources",
-					"CheckResources: Invalid Resource Type.",
-				)
-				return
+			} else {
+				href, hrefErr := checkResourceHref(deviceQuery.ResourceType, intentResource)
+				if hrefErr != nil {
+					diags.AddError(
+						"Error Read CheckResources",
+						"CheckResources: Device "+deviceQuery.N.ValueString()+": "+hrefErr.Error(),
+					)
+					return
+				}
+				var body []byte
+				body, deviceId, err = client.ExecuteDeviceHttpCommandNoCache(deviceQuery.N.ValueString(), "GET", "resources"+href, nil)
+				if err == nil {
+					err = json.Unmarshal(body, &data2)
+				}
 			}
 
-			if err != nil && !strings.Contains(err.Error(), "status: 404") {
-				diags.AddError(
-					"Error Read CheckResources",
-					"CheckResources: Could not GET Resources, unexpected error: "+err.Error(),
-				)
-				return
+			if err != nil {
+				if !strings.Contains(err.Error(), "status: 404") {
+					diags.AddError(
+						"Error Read CheckResources",
+						"CheckResources: Could not GET Resources, unexpected error: "+err.Error(),
+					)
+					return
+				}
+				tflog.Warn(ctx, "CheckResources: resource not found ## 404", map[string]interface{}{"Device": deviceQuery.N.ValueString(), "resource": intentResource})
+				for index3 := range intentResource.AttributeValues {
+					deviceQuery.Resources[index2].AttributeValues[index3].IsValueMatch = types.BoolValue(false)
+					deviceQuery.Resources[index2].AttributeValues[index3].DeviceValue = types.StringNull()
+				}
+				continue
 			}
 
 			//deviceQuery.DeviceId = types.StringValue(deviceId}
@@ -260,60 +363,194 @@ func (d *CheckResourcesDataSource) Read(ctx context.Context, req datasource.Read
 
 			tflog.Debug(ctx, "CheckResources: Get Device Resource", map[string]interface{}{"Device Resource": data2})
 
-			data3 := data2["data"].(map[string]interface{})
-			resource := data3["content"].(map[string]interface{})
-			deviceQuery.Resources[index2].Id = types.StringValue(deviceQuery.N.ValueString() + data3["resourceId"].(map[string]interface{})["href"].(string))
-			if resource["aid"] != nil {
-				deviceQuery.Resources[index2].Aid = types.StringValue(resource["aid"].(string))
+			data3, ok := data2["data"].(map[string]interface{})
+			var resource map[string]interface{}
+			if ok {
+				resource, ok = data3["content"].(map[string]interface{})
+			}
+			if !ok {
+				diags.AddError(
+					"Error Read CheckResources",
+					"CheckResources: Device "+deviceQuery.N.ValueString()+": response has no content: "+fmt.Sprint(data2),
+				)
+				return
+			}
+			if resourceId, ok := data3["resourceId"].(map[string]interface{}); ok {
+				if href, ok := resourceId["href"].(string); ok {
+					deviceQuery.Resources[index2].Id = types.StringValue(deviceQuery.N.ValueString() + href)
+				}
+			}
+			if aid, ok := resource["aid"].(string); ok {
+				deviceQuery.Resources[index2].Aid = types.StringValue(aid)
 			}
 			for index3, v := range intentResource.AttributeValues {
 				tflog.Debug(ctx, "CheckResources: Get intent Resource IntentValue", map[string]interface{}{"IntentValue": v})
-				var rawValue = resource[v.Attribute.ValueString()]
-				var match bool = true
-				var strValue string = ""
-				if !v.Attribute.IsNull() && rawValue != nil {
-					valueType := reflect.TypeOf(rawValue).String()
-					//tflog.Debug(ctx, "CheckResources: Get intent Resource IntentValue 1111", "valueType", valueType)
-					if valueType == "int" {
-						value, _ := strconv.ParseInt(v.IntentValue.ValueString(), 10, 0)
-						if rawValue != value {
-							match = false
-						}
-						strValue = strconv.Itoa(rawValue.(int))
-					} else if valueType == "float64" {
-						value, _ := strconv.ParseFloat(v.IntentValue.ValueString(), 64)
-						if rawValue != value {
-							match = false
-						}
-						strValue = fmt.Sprintf("%.2f", rawValue.(float64))
-					} else if valueType == "bool" {
-						value, _ := strconv.ParseBool(v.IntentValue.ValueString())
-						if rawValue != value {
-							match = false
-						}
-						strValue = strconv.FormatBool(rawValue.(bool))
-					} else {
-						if v.IntentValue.ValueString() != rawValue.(string) {
-							match = false
-						}
-						strValue = rawValue.(string)
+				rawValue, ok := lookupPath(resource, v.Attribute.ValueString())
+				if !ok {
+					deviceQuery.Resources[index2].AttributeValues[index3].IsValueMatch = types.BoolValue(false)
+				}
+				if !v.Attribute.IsNull() && ok {
+					match, err := compareValue(v.Operator.ValueString(), rawValue, v.IntentValue.ValueString(), v.Tolerance.ValueFloat64())
+					if err != nil {
+						diags.AddError(
+							"Error Read CheckResources",
+							"CheckResources: Could not compare "+v.Attribute.ValueString()+" of "+deviceQuery.Resources[index2].Id.ValueString()+", error: "+err.Error(),
+						)
+						return
 					}
-					//tflog.Debug(ctx, "CheckResources: Get intent Resource IntentValue 2222", "v.IntentValue.Value", v.IntentValue.Value, "match", match)
 					deviceQuery.Resources[index2].AttributeValues[index3].IsValueMatch = types.BoolValue(match)
-					deviceQuery.Resources[index2].AttributeValues[index3].DeviceValue = types.StringValue(strValue)
+					deviceQuery.Resources[index2].AttributeValues[index3].DeviceValue = types.StringValue(formatValue(rawValue))
 				}
-				deviceQuery.Resources[index2].AttributeValues[index3].AttributeControlByHost = types.BoolValue(false)
-				if !v.ControlAttribute.IsNull() { //&& resource[v.ControlAttribute.Value] != nil {
-					attributeControl := os.Getenv("TF_" + v.ControlAttribute.ValueString())
-					if attributeControl == "Host" {
-						deviceQuery.Resources[index2].AttributeValues[index3].AttributeControlByHost = types.BoolValue(true)
-					}
+				deviceQuery.Resources[index2].AttributeValues[index3].AttributeControlByHost = types.BoolValue(hostControlled(resource, v.Attribute.ValueString(), v.ControlAttribute.ValueString()))
+			}
+		}
+
+	}
+}
+
+// checkWait returns the timeout and poll interval of wait_until_match, 5m and
+// 10s by default.
+func checkWait(data ResourcesDataSourceData, diags *diag.Diagnostics) (time.Duration, time.Duration) {
+	waitTimeout, pollInterval := 5*time.Minute, 10*time.Second
+	for _, v := range []struct {
+		attribute string
+		value     types.String
+		duration  *time.Duration
+	}{
+		{"timeout", data.Timeout, &waitTimeout},
+		{"poll_interval", data.PollInterval, &pollInterval},
+	} {
+		if v.value.IsNull() {
+			continue
+		}
+		duration, err := time.ParseDuration(v.value.ValueString())
+		if err != nil || duration <= 0 {
+			diags.AddAttributeError(
+				path.Root(v.attribute),
+				"Error Read CheckResources",
+				"CheckResources: "+v.attribute+" must be a positive duration, e.g. 30s, got "+v.value.ValueString(),
+			)
+			continue
+		}
+		*v.duration = duration
+	}
+	return waitTimeout, pollInterval
+}
+
+// checkMismatches returns the attribute values that do not match their intent,
+// or were not found, as device, href and attribute.
+func checkMismatches(data ResourcesDataSourceData) []string {
+	var mismatches []string
+	for _, entry := range checkResourcesReport(data).Results {
+		if !entry.Match {
+			mismatches = append(mismatches, "- "+entry.Device+entry.Href+" "+entry.Attribute+": intent "+entry.Operator+" "+entry.IntentValue+", device "+entry.DeviceValue)
+		}
+	}
+	return mismatches
+}
+
+// checkResourcesReport returns the report of the attribute values of the
+// checked resources. A resource that was not found has no device values.
+func checkResourcesReport(data ResourcesDataSourceData) *checkReport {
+	report := newCheckReport("xrcm_check_resources")
+	for _, query := range data.Queries {
+		for _, r := range query.Resources {
+			href := r.Href.ValueString()
+			if len(r.Id.ValueString()) > 0 {
+				href = strings.TrimPrefix(r.Id.ValueString(), query.N.ValueString())
+			}
+			for _, v := range r.AttributeValues {
+				operator := v.Operator.ValueString()
+				if len(operator) == 0 {
+					operator = operatorEq
 				}
+				report.add(checkReportEntry{
+					Device:      query.N.ValueString(),
+					Href:        href,
+					Attribute:   v.Attribute.ValueString(),
+					Operator:    operator,
+					IntentValue: v.IntentValue.ValueString(),
+					DeviceValue: v.DeviceValue.ValueString(),
+					Match:       v.IsValueMatch.ValueBool(),
+				})
 			}
 		}
+	}
+	return report
+}
+
+// checkResourceType is a resource type of the queries, its href a template of
+// the ids of the resource.
+type checkResourceType struct {
+	names []string
+	href  string
+}
+
+var checkResourceTypes = []checkResourceType{
+	{names: []string{"Carrier", "xrcm_carrier"}, href: "/lineptps/{parentid}/carriers/{resourceid}"},
+	{names: []string{"DSC", "xrcm_dsc"}, href: "/lineptps/{grandparentid}/carriers/{parentid}/dscs/{resourceid}"},
+	{names: []string{"DSCG", "xrcm_dscg"}, href: "/lineptps/{grandparentid}/carriers/{parentid}/dscgs/{resourceid}"},
+	{names: []string{"Ethernet", "xrcm_ethernet"}, href: "/ethernets/{resourceid}"},
+	{names: []string{"AC", "xrcm_ac"}, href: "/ethernets/{parentid}/acs/{resourceid}"},
+	{names: []string{"LC", "xrcm_lc"}, href: "/lcs/{resourceid}"},
+	{names: []string{"Config", "xrcm_cfg"}, href: "/cfg"},
+	{names: []string{"OTU", "xrcm_otu"}, href: "/otus/{resourceid}"},
+	{names: []string{"ODU", "xrcm_odu"}, href: "/otus/{parentid}/odus/{resourceid}"},
+	{names: []string{"LinePTP", "xrcm_lineptp"}, href: "/lineptps/{resourceid}"},
+	{names: []string{"CarrierDiag", "xrcm_carrier_diag"}, href: "/lineptps/{parentid}/carriers/{resourceid}/diagnostic"},
+	{names: []string{"DSCDiag", "xrcm_dsc_diag"}, href: "/lineptps/{grandparentid}/carriers/{parentid}/dscs/{resourceid}/diagnostic"},
+	{names: []string{"EthernetDiag", "xrcm_ethernet_diag"}, href: "/ethernets/{resourceid}/diagnostic"},
+	{names: []string{"OTUDiag", "xrcm_otu_diag"}, href: "/otus/{resourceid}/diagnostic"},
+	{names: []string{"LLDP", "xrcm_ethernet_lldp"}, href: "/ethernets/{resourceid}/lldp-cfg"},
+	{names: []string{"HostNeighbors", "xrcm_host_neighbors"}, href: "/ethernets/{resourceid}/host-neighbors"},
+	{names: []string{"LineNeighbors", "xrcm_line_neighbors"}, href: "/lineptps/{resourceid}/neighbors"},
+}
+
+// checkResourceHref returns the href of a resource of a query, its own href or
+// the href of its resource type and ids.
+func checkResourceHref(resourceType types.String, r ResourceData) (string, error) {
+	if !r.Href.IsNull() {
+		href := r.Href.ValueString()
+		if !strings.HasPrefix(href, "/") {
+			href = "/" + href
+		}
+		return href, nil
+	}
+	if resourceType.IsNull() {
+		return "", errors.New("set either the resourcetype of the query or the href of the resource")
+	}
+
+	for _, t := range checkResourceTypes {
+		if t.names[0] != resourceType.ValueString() && t.names[1] != resourceType.ValueString() {
+			continue
+		}
+		ids := map[string]types.String{
+			"{grandparentid}": r.GrandparentId,
+			"{parentid}":      r.ParentId,
+			"{resourceid}":    r.ResourceId,
+		}
+		href := t.href
+		var missing []string
+		for _, placeholder := range []string{"{grandparentid}", "{parentid}", "{resourceid}"} {
+			if !strings.Contains(href, placeholder) {
+				continue
+			}
+			id := ids[placeholder]
+			if id.IsNull() || len(id.ValueString()) == 0 {
+				missing = append(missing, strings.Trim(placeholder, "{}"))
+				continue
+			}
+			href = strings.ReplaceAll(href, placeholder, id.ValueString())
+		}
+		if len(missing) > 0 {
+			return "", fmt.Errorf("resource type %s needs %s", resourceType.ValueString(), strings.Join(missing, ", "))
+		}
+		return href, nil
+	}
 
-		tflog.Debug(ctx, "CheckResources: Check Resources", map[string]interface{}{"resourcesDataSourceData": resourcesDataSourceData})
-		diags = resp.State.Set(ctx, &resourcesDataSourceData)
-		resp.Diagnostics.Append(diags...)
+	var names []string
+	for _, t := range checkResourceTypes {
+		names = append(names, t.names[0])
 	}
+	return "", fmt.Errorf("invalid resource type %s, expected Device, %s, a provider resource name or an href", resourceType.ValueString(), strings.Join(names, ", "))
 }
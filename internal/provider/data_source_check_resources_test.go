@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fakeCheckClient answers each GET of a resource with the next of its
+// responses, the last one once they are used up.
+type fakeCheckClient struct {
+	responses []string
+	gets      int
+}
+
+func (c *fakeCheckClient) ResolveDevice(devicename string) (string, error) {
+	return "id-" + devicename, nil
+}
+
+func (c *fakeCheckClient) ExecuteHttpCommand(command, commanduri string, commandBody []byte) ([]byte, error) {
+	return nil, errors.New("unexpected command " + command + " " + commanduri)
+}
+
+func (c *fakeCheckClient) ExecuteDeviceHttpCommandNoCache(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error) {
+	i := c.gets
+	if i >= len(c.responses) {
+		i = len(c.responses) - 1
+	}
+	c.gets++
+	return []byte(c.responses[i]), "id-" + devicename, nil
+}
+
+func TestCheckUntilMatch(t *testing.T) {
+	config := func(data *ResourcesDataSourceData) diag.Diagnostics {
+		*data = ResourcesDataSourceData{
+			WaitUntilMatch: types.BoolValue(true),
+			Timeout:        types.StringValue("1s"),
+			PollInterval:   types.StringValue("1ms"),
+			ReportPath:     types.StringNull(),
+			ReportFormat:   types.StringNull(),
+			Queries: []ResourceDataSourceData{{
+				N:            types.StringValue("xr-hub"),
+				ResourceType: types.StringValue("Carrier"),
+				Resources: []ResourceData{{
+					Href: types.StringValue("/lineptps/1/carriers/1"),
+					AttributeValues: []AttributeValuesData{{
+						Attribute:        types.StringValue("configState"),
+						IntentValue:      types.StringValue("completed"),
+						Operator:         types.StringNull(),
+						ControlAttribute: types.StringNull(),
+					}},
+				}},
+			}},
+		}
+		return nil
+	}
+	client := &fakeCheckClient{responses: []string{
+		`{"data":{"resourceId":{"href":"/lineptps/1/carriers/1"},"content":{"aid":"XR-L1-C1","configState":"pending"}}}`,
+		`{"data":{"resourceId":{"href":"/lineptps/1/carriers/1"},"content":{"aid":"XR-L1-C1","configState":"completed"}}}`,
+	}}
+
+	var data ResourcesDataSourceData
+	config(&data)
+	var diags diag.Diagnostics
+	data = checkUntilMatch(context.Background(), client, data, config, &diags)
+
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("checkUntilMatch() diagnostics = %v", diags)
+	}
+	if client.gets != 2 {
+		t.Errorf("checkUntilMatch() sent %d GETs, want 2", client.gets)
+	}
+	v := data.Queries[0].Resources[0].AttributeValues[0]
+	if v.IsValueMatch != types.BoolValue(true) || v.DeviceValue != types.StringValue("completed") {
+		t.Errorf("checkUntilMatch() attribute = %v %v, want match of completed", v.IsValueMatch, v.DeviceValue)
+	}
+}
+
+func TestCheckUntilMatchTimeout(t *testing.T) {
+	config := func(data *ResourcesDataSourceData) diag.Diagnostics {
+		*data = ResourcesDataSourceData{
+			WaitUntilMatch: types.BoolValue(true),
+			Timeout:        types.StringValue("20ms"),
+			PollInterval:   types.StringValue("5ms"),
+			Queries: []ResourceDataSourceData{{
+				N: types.StringValue("xr-hub"),
+				Resources: []ResourceData{{
+					Href: types.StringValue("/lineptps/1/carriers/1"),
+					AttributeValues: []AttributeValuesData{{
+						Attribute:   types.StringValue("configState"),
+						IntentValue: types.StringValue("completed"),
+					}},
+				}},
+			}},
+		}
+		return nil
+	}
+	client := &fakeCheckClient{responses: []string{
+		`{"data":{"resourceId":{"href":"/lineptps/1/carriers/1"},"content":{"configState":"pending"}}}`,
+	}}
+
+	var data ResourcesDataSourceData
+	config(&data)
+	var diags diag.Diagnostics
+	checkUntilMatch(context.Background(), client, data, config, &diags)
+
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("checkUntilMatch() diagnostics = %v, want the Intent Not Matched warning", diags)
+	}
+	if client.gets < 2 {
+		t.Errorf("checkUntilMatch() sent %d GETs, want at least 2", client.gets)
+	}
+}
+
+// notFoundCheckClient answers every GET of a resource with a 404.
+type notFoundCheckClient struct {
+	fakeCheckClient
+}
+
+func (c *notFoundCheckClient) ExecuteDeviceHttpCommandNoCache(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error) {
+	c.gets++
+	return nil, "", errors.New("ExecuteDeviceHttpCommand: status: 404, " + commanduri)
+}
+
+func TestCheckQueriesNotMatched(t *testing.T) {
+	newData := func(attribute string) *ResourcesDataSourceData {
+		return &ResourcesDataSourceData{
+			Queries: []ResourceDataSourceData{{
+				N: types.StringValue("xr-hub"),
+				Resources: []ResourceData{{
+					Href: types.StringValue("/lineptps/1/carriers/1"),
+					AttributeValues: []AttributeValuesData{{
+						Attribute:   types.StringValue(attribute),
+						IntentValue: types.StringValue("completed"),
+					}},
+				}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		client    checkClient
+		attribute string
+		wantError bool
+	}{
+		{"resource not found", &notFoundCheckClient{}, "configState", false},
+		{"attribute not found", &fakeCheckClient{responses: []string{`{"data":{"resourceId":{"href":"/lineptps/1/carriers/1"},"content":{"aid":"XR-L1-C1"}}}`}}, "configState", false},
+		{"no content", &fakeCheckClient{responses: []string{`{"data":{"resourceId":{"href":"/lineptps/1/carriers/1"}}}`}}, "configState", true},
+		{"no data", &fakeCheckClient{responses: []string{`{}`}}, "configState", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := newData(tt.attribute)
+			var diags diag.Diagnostics
+			checkQueries(context.Background(), tt.client, data, &diags)
+			if diags.HasError() != tt.wantError {
+				t.Fatalf("checkQueries() errors = %v, want error %v", diags, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			v := data.Queries[0].Resources[0].AttributeValues[0]
+			if v.IsValueMatch != types.BoolValue(false) || !v.DeviceValue.IsNull() {
+				t.Errorf("checkQueries() attribute = %v %v, want no match and no device value", v.IsValueMatch, v.DeviceValue)
+			}
+			if len(checkMismatches(*data)) != 1 {
+				t.Errorf("checkMismatches() = %v, want the attribute", checkMismatches(*data))
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package provider
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -31,10 +34,21 @@ type CheckData struct {
 	Condition   types.Bool   `tfsdk:"condition"`
 	Description types.String `tfsdk:"description"`
 	Throw       types.String `tfsdk:"throw"`
+	Severity    types.String `tfsdk:"severity"`
+}
+
+type CheckResultData struct {
+	Index       types.Int64  `tfsdk:"index"`
+	Description types.String `tfsdk:"description"`
+	Severity    types.String `tfsdk:"severity"`
+	Passed      types.Bool   `tfsdk:"passed"`
 }
 
 type ChecksDataSourceData struct {
-	Checks []CheckData `tfsdk:"checks"`
+	Checks       []CheckData       `tfsdk:"checks"`
+	Results      []CheckResultData `tfsdk:"results"`
+	ReportPath   types.String      `tfsdk:"report_path"`
+	ReportFormat types.String      `tfsdk:"report_format"`
 }
 
 // Metadata returns the data source type name.
@@ -45,7 +59,7 @@ func (d *ChecksDataSource) Metadata(_ context.Context, req datasource.MetadataRe
 func (d *ChecksDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: " Multiple Checks",
-		Attributes: map[string]schema.Attribute{
+		Attributes: reportAttributes(map[string]schema.Attribute{
 			"checks": schema.ListNestedAttribute{
 				Description: "List of device ids.",
 				Required:    true,
@@ -57,16 +71,41 @@ func (d *ChecksDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 						},
 						"description": schema.StringAttribute{
 							Description: "description",
-							Computed:    true,
+							Optional:    true,
 						},
 						"throw": schema.StringAttribute{
 							Description: "throw",
 							Optional:    true,
 						},
+						"severity": checkSeverityAttribute(),
+					},
+				},
+			},
+			"results": schema.ListNestedAttribute{
+				Description: "Result of each check, in the order of checks",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"index": schema.Int64Attribute{
+							Description: "index of the check",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "description",
+							Computed:    true,
+						},
+						"severity": schema.StringAttribute{
+							Description: "severity",
+							Computed:    true,
+						},
+						"passed": schema.BoolAttribute{
+							Description: "The condition of the check is false",
+							Computed:    true,
+						},
 					},
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -91,13 +130,81 @@ func (d *ChecksDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	tflog.Debug(ctx, "ChecksDataSource: get Checks, request", map[string]interface{}{"ChecksData": ChecksData})
 
-	for _, check := range ChecksData.Checks {
-		if check.Condition.ValueBool() {
-			resp.Diagnostics.AddError(
-				"Checks Condition Failed!!! << "+check.Description.ValueString()+" >>",
-				"Error: "+check.Throw.ValueString(),
-			)
+	var failures = map[string][]string{}
+	report := newCheckReport("xrcm_checks")
+	ChecksData.Results = make([]CheckResultData, 0, len(ChecksData.Checks))
+	for i, check := range ChecksData.Checks {
+		severity := checkSeverity(check.Severity, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+		passed := !check.Condition.ValueBool()
+		ChecksData.Results = append(ChecksData.Results, CheckResultData{
+			Index:       types.Int64Value(int64(i)),
+			Description: check.Description,
+			Severity:    types.StringValue(severity),
+			Passed:      types.BoolValue(passed),
+		})
+		report.add(checkReportEntry{
+			Description: check.Description.ValueString(),
+			Severity:    severity,
+			Match:       passed,
+		})
+		if !passed {
+			failures[severity] = append(failures[severity], "- "+check.Description.ValueString()+": "+check.Throw.ValueString())
+		}
+	}
+
+	tflog.Debug(ctx, "ChecksDataSource: get Checks, results", map[string]interface{}{"results": ChecksData.Results})
+
+	diags = resp.State.Set(ctx, &ChecksData)
+	resp.Diagnostics.Append(diags...)
+	if err := report.write(ChecksData.ReportPath, ChecksData.ReportFormat); err != nil {
+		resp.Diagnostics.AddError(
+			"Error Read Checks",
+			"Checks: Could not write report "+ChecksData.ReportPath.ValueString()+", unexpected error: "+err.Error(),
+		)
+	}
+
+	total := strconv.Itoa(len(ChecksData.Checks))
+	if warnings := failures[checkSeverityWarning]; len(warnings) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Checks Condition Failed!!! << "+strconv.Itoa(len(warnings))+" of "+total+" warning checks >>",
+			strings.Join(warnings, "\n"),
+		)
+	}
+	if errs := failures[checkSeverityError]; len(errs) > 0 {
+		resp.Diagnostics.AddError(
+			"Checks Condition Failed!!! << "+strconv.Itoa(len(errs))+" of "+total+" checks >>",
+			strings.Join(errs, "\n"),
+		)
+	}
+}
+
+const (
+	checkSeverityError   = "error"
+	checkSeverityWarning = "warning"
+)
+
+// checkSeverityAttribute is the schema of the severity of a check.
+func checkSeverityAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "error (default) fails the read, warning only reports the failed check",
+		Optional:    true,
+	}
+}
+
+// checkSeverity returns the severity of a check, error by default.
+func checkSeverity(severity types.String, diags *diag.Diagnostics) string {
+	switch s := strings.ToLower(severity.ValueString()); s {
+	case "", checkSeverityError:
+		return checkSeverityError
+	case checkSeverityWarning:
+		return s
 	}
+	diags.AddError(
+		"Error Read Checks",
+		"Checks: Invalid severity "+severity.ValueString()+", expected error or warning",
+	)
+	return ""
 }
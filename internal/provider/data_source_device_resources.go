@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &DeviceResourcesDataSource{}
+	_ datasource.DataSourceWithConfigure = &DeviceResourcesDataSource{}
+)
+
+// NewDeviceResourcesDataSource is a helper function to simplify the provider implementation.
+func NewDeviceResourcesDataSource() datasource.DataSource {
+	return &DeviceResourcesDataSource{}
+}
+
+// DeviceResourcesDataSource is the data source implementation.
+type DeviceResourcesDataSource struct {
+	client *xrcm_pf.Client
+}
+
+type DeviceResourceData struct {
+	Id            types.String   `tfsdk:"id"`
+	N             types.String   `tfsdk:"n"`
+	DeviceId      types.String   `tfsdk:"deviceid"`
+	Href          types.String   `tfsdk:"href"`
+	ResourceTypes []types.String `tfsdk:"resourcetypes"`
+	Content       types.String   `tfsdk:"content"`
+}
+
+type DeviceResourcesDataSourceData struct {
+	N            types.String         `tfsdk:"n"`
+	Names        []types.String       `tfsdk:"names"`
+	ResourceType types.String         `tfsdk:"resourcetype"`
+	HrefPrefix   types.String         `tfsdk:"hrefprefix"`
+	Resources    []DeviceResourceData `tfsdk:"resources"`
+}
+
+// Metadata returns the data source type name.
+func (d *DeviceResourcesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_resources"
+}
+
+func (d *DeviceResourcesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the content of any device resources matching a resource type and/or href prefix",
+		Attributes: map[string]schema.Attribute{
+			"n": schema.StringAttribute{
+				Description: "Device Name",
+				Optional:    true,
+			},
+			"names": schema.ListAttribute{
+				Description: "List of Device Names",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"resourcetype": schema.StringAttribute{
+				Description: "Resource type to match against any of the resource types of a link, e.g. xr.otu",
+				Optional:    true,
+			},
+			"hrefprefix": schema.StringAttribute{
+				Description: "Href prefix to match, e.g. /lineptps/1/",
+				Optional:    true,
+			},
+			"resources": schema.ListNestedAttribute{
+				Description: "List of matching resources",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "TF id",
+							Computed:    true,
+						},
+						"n": schema.StringAttribute{
+							Description: "Device Name",
+							Computed:    true,
+						},
+						"deviceid": schema.StringAttribute{
+							Description: "device id",
+							Computed:    true,
+						},
+						"href": schema.StringAttribute{
+							Description: "Resource href",
+							Computed:    true,
+						},
+						"resourcetypes": schema.ListAttribute{
+							Description: "Resource types",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"content": schema.StringAttribute{
+							Description: "Resource content, as JSON",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DeviceResourcesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*xrcm_pf.Client)
+}
+
+func (d *DeviceResourcesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	queryData := DeviceResourcesDataSourceData{}
+
+	diags := req.Config.Get(ctx, &queryData)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "DeviceResourcesDataSource: get resources", map[string]interface{}{"queryData": queryData})
+
+	names := queryData.Names
+	if !queryData.N.IsNull() && len(queryData.N.ValueString()) > 0 {
+		names = append([]types.String{queryData.N}, names...)
+	}
+	if len(names) == 0 {
+		resp.Diagnostics.AddError(
+			"Error Read DeviceResourcesDataSource",
+			"DeviceResourcesDataSource: n or names must be specified",
+		)
+		return
+	}
+
+	deviceResources := make([][]DeviceResourceData, len(names))
+	deviceDiags := make([]diag.Diagnostics, len(names))
+
+	forEachParallel(d.client.MaxConcurrency, len(names), func(i int) {
+		n := names[i]
+		data, deviceId, err := GetResource(ctx, d.client, n.ValueString(), "resource-links")
+
+		if err != nil {
+			deviceDiags[i].AddError(
+				"Error Read DeviceResourcesDataSource",
+				"DeviceResourcesDataSource: Could not GET resource-links of "+n.ValueString()+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		links, _ := data["resources"].([]interface{})
+
+		for _, v := range links {
+			rec, _ := v.(map[string]interface{})
+			href, _ := rec["href"].(string)
+			rsTypes, _ := rec["resourceTypes"].([]interface{})
+
+			if !matchResourceLink(href, rsTypes, queryData.ResourceType.ValueString(), queryData.HrefPrefix.ValueString()) {
+				continue
+			}
+
+			data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources"+href)
+			if err != nil {
+				tflog.Debug(ctx, "DeviceResourcesDataSource: skip resource", map[string]interface{}{"href": href, "error": err.Error()})
+				continue
+			}
+
+			resultData2, _ := data2["data"].(map[string]interface{})
+			content, err := json.Marshal(resultData2["content"])
+			if err != nil {
+				continue
+			}
+
+			resourceData := DeviceResourceData{}
+			resourceData.Id = types.StringValue(n.ValueString() + href)
+			resourceData.N = n
+			resourceData.DeviceId = types.StringValue(deviceId)
+			resourceData.Href = types.StringValue(href)
+			for _, rt := range rsTypes {
+				if s, ok := rt.(string); ok {
+					resourceData.ResourceTypes = append(resourceData.ResourceTypes, types.StringValue(s))
+				}
+			}
+			resourceData.Content = types.StringValue(string(content))
+			deviceResources[i] = append(deviceResources[i], resourceData)
+		}
+	})
+
+	for _, deviceDiag := range deviceDiags {
+		resp.Diagnostics.Append(deviceDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var resources []DeviceResourceData
+	for _, r := range deviceResources {
+		resources = append(resources, r...)
+	}
+
+	tflog.Debug(ctx, "DeviceResourcesDataSource: get resources", map[string]interface{}{"resources": len(resources)})
+	queryData.Resources = resources
+	diags = resp.State.Set(ctx, &queryData)
+	resp.Diagnostics.Append(diags...)
+}
+
+// matchResourceLink reports whether a resource link has the resource type
+// (any of its types) and href prefix asked for. Empty filters match all.
+func matchResourceLink(href string, rsTypes []interface{}, resourceType string, hrefPrefix string) bool {
+	if len(href) == 0 {
+		return false
+	}
+	if len(hrefPrefix) > 0 && !strings.HasPrefix(href, hrefPrefix) {
+		return false
+	}
+	if len(resourceType) == 0 {
+		return true
+	}
+	for _, rt := range rsTypes {
+		if rt == resourceType {
+			return true
+		}
+	}
+	return false
+}
@@ -3,9 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -144,6 +145,10 @@ func (d *DevicesDataSource) Read(ctx context.Context, req datasource.ReadRequest
 			var entity = make(map[string]interface{})
 			json.Unmarshal([]byte(v), &entity)
 			result := entity["result"].(map[string]interface{})
+			// Devices the provider may not address are not listed.
+			if d.client.CheckDevice(result["name"].(string)) != nil {
+				continue
+			}
 
 			connection := (result["metadata"].(map[string]interface{}))["connection"]
 			status := connection.(map[string]interface{})["status"].(string)
@@ -171,8 +176,14 @@ func (d *DevicesDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		queryStr = "devices"
 		for _, name := range data.Names {
 			queryStr = "devices"
-			deviceId, found := d.client.GetDeviceIdFromName(name.ValueString())
-			if !found {
+			deviceId, err := d.client.ResolveDevice(name.ValueString())
+			if errors.Is(err, xrcm_pf.ErrDeviceNotAllowed) {
+				resp.Diagnostics.AddError(
+					"DevicesDataSource: read ##: Error Get Device: "+name.ValueString(),
+					"Read: "+err.Error(),
+				)
+				return
+			} else if err != nil {
 				continue
 			}
 			queryStr += "/" + deviceId
@@ -3,13 +3,16 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	//"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -56,13 +59,15 @@ type DetailDeviceData struct {
 	MacAddress          types.String `tfsdk:"macaddress"`
 	ConnectorType       types.String `tfsdk:"connectortype"`
 	FormFactor          types.String `tfsdk:"formfactor"`
+	Error               types.String `tfsdk:"error"`
 	//Capabilities        types.Map    `tfsdk:"capabilities"`
 }
 
 type DetailDevicesDataSourceData struct {
-	State   types.String       `tfsdk:"state"`
-	Names   []types.String     `tfsdk:"names"`
-	Devices []DetailDeviceData `tfsdk:"devices"`
+	State           types.String       `tfsdk:"state"`
+	Names           []types.String     `tfsdk:"names"`
+	Devices         []DetailDeviceData `tfsdk:"devices"`
+	SkipUnavailable types.Bool         `tfsdk:"skip_unavailable"`
 }
 
 // Metadata returns the data source type name.
@@ -83,6 +88,10 @@ func (d *DetailDevicesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 				Required:    true,
 				ElementType: types.StringType,
 			},
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the devices that could be read instead of failing, and the others with only their name and error, including the devices not found",
+				Optional:    true,
+			},
 			"devices": schema.ListNestedAttribute{
 				Description: "List of devices'infos",
 				Computed:    true,
@@ -116,6 +125,10 @@ func (d *DetailDevicesDataSource) Schema(_ context.Context, _ datasource.SchemaR
 							Description: "Device status",
 							Computed:    true,
 						},
+						"error": schema.StringAttribute{
+							Description: "Why the device could not be read",
+							Computed:    true,
+						},
 						"configuredrole": schema.StringAttribute{
 							Description: "Device configured role",
 							Computed:    true,
@@ -226,83 +239,104 @@ func (d *DetailDevicesDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
-	var devices []DetailDeviceData
-	for _, name := range data.Names {
-		deviceId, found := d.client.GetDeviceIdFromName(name.ValueString())
-		if !found {
-			continue
+	deviceResults := make([]*DetailDeviceData, len(data.Names))
+	deviceDiags := make([]diag.Diagnostics, len(data.Names))
+	forEachParallel(d.client.MaxConcurrency, len(data.Names), func(i int) {
+		name := data.Names[i]
+		deviceData := DetailDeviceData{}
+		// unavailable records a device that could not be read: with
+		// skip_unavailable in its error, otherwise failing the read if fail,
+		// or else leaving the device out as devices not found always were.
+		unavailable := func(detail string, fail bool) {
+			if !data.SkipUnavailable.ValueBool() {
+				if fail {
+					deviceDiags[i].AddError("DetailDevicesDataSource: read ##: Error Get Device: "+name.ValueString(), detail)
+				}
+				return
+			}
+			deviceData.N = name
+			deviceData.Error = types.StringValue(detail)
+			deviceResults[i] = &deviceData
+		}
+
+		deviceId, err := d.client.ResolveDevice(name.ValueString())
+		if errors.Is(err, xrcm_pf.ErrDeviceNotAllowed) {
+			unavailable("Read: "+err.Error(), true)
+			return
+		} else if err != nil {
+			unavailable("Read: Could not find device "+name.ValueString(), false)
+			return
 		}
 		queryStr := "devices/" + deviceId
 
 		body, err := d.client.ExecuteHttpCommand("GET", queryStr, nil)
 		if err != nil {
 			if !strings.Contains(err.Error(), "status: 404") {
-				diags.AddError(
-					"DetailDevicesDataSource: read ##: Error Get Device: "+name.ValueString(),
-					"Read: Could not Get , unexpected error: "+err.Error(),
-				)
+				unavailable("Read: Could not Get , unexpected error: "+err.Error(), true)
 				return
 			}
 			tflog.Debug(ctx, "DetailDevicesDataSource: read - not found device "+name.ValueString())
-			continue
+			unavailable("Read: Could not find device "+name.ValueString(), false)
+			return
 		}
 
 		tflog.Debug(ctx, "DetailDevicesDataSource Read: get device", map[string]interface{}{"queryStr": queryStr, "body": string(body)})
-		var result = make(map[string]interface{})
-		json.Unmarshal([]byte(body), &result)
-		deviceData := DetailDeviceData{}
-		connection := (result["metadata"].(map[string]interface{}))["connection"]
-		status := connection.(map[string]interface{})["status"].(string)
+		var device xrcm.Device
+		if err := json.Unmarshal(body, &device); err != nil {
+			unavailable("Read: Could not parse device "+name.ValueString()+", unexpected error: "+err.Error(), true)
+			return
+		}
+		status := device.Metadata.Connection.Status
 		if data.State.ValueString() != "" && data.State.ValueString() != status {
-			continue
+			return
 		}
-		deviceData.DeviceId = types.StringValue(result["id"].(string))
-		manu := (result["manufacturerName"].([]interface{}))[0]
-		manuValue := manu.(map[string]interface{})["value"].(string)
-		deviceData.ManufacturerName = types.StringValue(manuValue)
-		deviceData.N = types.StringValue(result["name"].(string))
+		deviceData.DeviceId = types.StringValue(device.Id)
+		deviceData.ManufacturerName = types.StringValue(device.Manufacturer())
+		deviceData.N = types.StringValue(device.Name)
 		deviceData.Status = types.StringValue(status)
-		xrTypes := result["types"].([]interface{})
-		deviceData.Type = types.StringValue(xrTypes[0].(string))
-		content := (result["data"].(map[string]interface{}))["content"].(map[string]interface{})
-		deviceData.SoftwareVersion = types.StringValue(content["sv"].(string))
-		deviceData.PIID = types.StringValue(content["piid"].(string))
+		if len(device.Types) > 0 {
+			deviceData.Type = types.StringValue(device.Types[0])
+		} else {
+			deviceData.Type = types.StringNull()
+		}
+		deviceData.SoftwareVersion = types.StringValue(device.Data.Content.Sv)
+		deviceData.PIID = types.StringValue(device.Data.Content.Piid)
+
+		client := xrcm.NewClientWithRequester(d.client)
 
 		//get device config
-		config, err := GetResourcebyID(ctx, d.client, deviceId, "resources/cfg")
+		cfg, err := client.GetCfg(ctx, name.ValueString())
 		if err != nil {
 			tflog.Debug(ctx, "DetailDevicesDataSource Read: get resources/cfg FAILED", map[string]interface{}{"device name": name.ValueString()})
-			continue
+			unavailable("Read: Could not Get resources/cfg, unexpected error: "+err.Error(), false)
+			return
 		}
-		resultData2 := config["data"].(map[string]interface{})
-		cfgRec := resultData2["content"].(map[string]interface{})
-		deviceData.ConfiguredRole = types.StringValue(cfgRec["configuredRole"].(string))
-		deviceData.CurrentRole = types.StringValue(cfgRec["currentRole"].(string))
-		deviceData.SerdesRate = types.StringValue(cfgRec["serdesRate"].(string))
-		deviceData.TrafficMode = types.StringValue(cfgRec["trafficMode"].(string))
-		deviceData.TcMode = types.BoolValue(cfgRec["tcMode"].(bool))
-		deviceData.RoleStatus = types.StringValue(cfgRec["roleStatus"].(string))
-		deviceData.RestartAction = types.StringValue(cfgRec["restartAction"].(string))
-		deviceData.FactoryResetAction = types.BoolValue(cfgRec["factoryResetAction"].(bool))
+		deviceData.ConfiguredRole = stringPointerValue(cfg.ConfiguredRole)
+		deviceData.CurrentRole = stringPointerValue(cfg.CurrentRole)
+		deviceData.SerdesRate = stringPointerValue(cfg.SerdesRate)
+		deviceData.TrafficMode = stringPointerValue(cfg.TrafficMode)
+		deviceData.TcMode = boolPointerValue(cfg.TcMode)
+		deviceData.RoleStatus = stringPointerValue(cfg.RoleStatus)
+		deviceData.RestartAction = stringPointerValue(cfg.RestartAction)
+		deviceData.FactoryResetAction = boolPointerValue(cfg.FactoryResetAction)
 
 		//get device config
-		platform, err := GetResourcebyID(ctx, d.client, deviceId, "resources/oic/p")
+		platform, err := client.GetPlatform(ctx, name.ValueString())
 		if err != nil {
 			tflog.Debug(ctx, "DetailDevicesDataSource Read: get resources/oic/p FAILED", map[string]interface{}{"device name": name.ValueString()})
-			continue
+			unavailable("Read: Could not Get resources/oic/p, unexpected error: "+err.Error(), false)
+			return
 		}
 		tflog.Debug(ctx, "DetailDevicesDataSource Read: get resources/oic/p SUCCESS", map[string]interface{}{"platform": platform})
-		resultData2 = platform["data"].(map[string]interface{})
-		platformRec := resultData2["content"].(map[string]interface{})
-		deviceData.Mnfv = types.StringValue(platformRec["mnfv"].(string))
-		deviceData.Mnmn = types.StringValue(platformRec["mnmn"].(string))
-		deviceData.Mnhw = types.StringValue(platformRec["mnhw"].(string))
-		deviceData.Mndt = types.StringValue(platformRec["mndt"].(string))
-		deviceData.Mnsel = types.StringValue(platformRec["mnsel"].(string))
-		deviceData.Clei = types.StringValue(platformRec["clei"].(string))
-		deviceData.MacAddress = types.StringValue(platformRec["macAddress"].(string))
-		deviceData.ConnectorType = types.StringValue(platformRec["connectorType"].(string))
-		deviceData.FormFactor = types.StringValue(platformRec["formFactor"].(string))
+		deviceData.Mnfv = stringPointerValue(platform.Mnfv)
+		deviceData.Mnmn = stringPointerValue(platform.Mnmn)
+		deviceData.Mnhw = stringPointerValue(platform.Mnhw)
+		deviceData.Mndt = stringPointerValue(platform.Mndt)
+		deviceData.Mnsel = stringPointerValue(platform.Mnsel)
+		deviceData.Clei = stringPointerValue(platform.Clei)
+		deviceData.MacAddress = stringPointerValue(platform.MacAddress)
+		deviceData.ConnectorType = stringPointerValue(platform.ConnectorType)
+		deviceData.FormFactor = stringPointerValue(platform.FormFactor)
 		/*if platformRec["capabilities"] != nil {
 			capMap := make(map[string]attr.Value)
 			for k, cap := range platformRec["capabilities"].(map[string]interface{})  {
@@ -310,7 +344,21 @@ func (d *DetailDevicesDataSource) Read(ctx context.Context, req datasource.ReadR
 			}
 			deviceData.Capabilities, _ = types.MapValue(types.StringType, capMap)
 		} */
-		devices = append(devices, deviceData)
+		deviceResults[i] = &deviceData
+	})
+
+	for _, deviceDiag := range deviceDiags {
+		resp.Diagnostics.Append(deviceDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var devices []DetailDeviceData
+	for _, deviceData := range deviceResults {
+		if deviceData != nil {
+			devices = append(devices, *deviceData)
+		}
 	}
 
 	data.Devices = make([]DetailDeviceData, len(devices))
@@ -4,10 +4,12 @@ import (
 	"context"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -29,15 +31,34 @@ type DSCGsDataSource struct {
 }
 
 type ModuleDSCGsDataSourceData struct {
-	N         types.String       `tfsdk:"n"`
-	LinePTPId types.String       `tfsdk:"lineptpid"`
-	CarrierId types.String       `tfsdk:"carrierid"`
-	DSCGIds   []types.String     `tfsdk:"dscgids"`
-	DSCGs     []DSCGResourceData `tfsdk:"dscgs"`
+	N         types.String   `tfsdk:"n"`
+	LinePTPId types.String   `tfsdk:"lineptpid"`
+	CarrierId types.String   `tfsdk:"carrierid"`
+	DSCGIds   []types.String `tfsdk:"dscgids"`
+	DSCGs     []DSCGData     `tfsdk:"dscgs"`
+	Status    types.String   `tfsdk:"status"`
+	Error     types.String   `tfsdk:"error"`
+}
+
+// DSCGData is a DSCG of the data source, the attributes of xrcm_dscg the
+// device reports.
+type DSCGData struct {
+	Id        types.String `tfsdk:"id"`
+	DeviceId  types.String `tfsdk:"deviceid"`
+	Aid       types.String `tfsdk:"aid"`
+	N         types.String `tfsdk:"n"`
+	LinePTPId types.String `tfsdk:"lineptpid"`
+	CarrierId types.String `tfsdk:"carrierid"`
+	DscgId    types.String `tfsdk:"dscgid"`
+	TxCDSCs   types.List   `tfsdk:"txdscs"`
+	RxCDSCs   types.List   `tfsdk:"rxdscs"`
+	IdleCDSCs types.List   `tfsdk:"idlecdscs"`
+	DscgCtrl  types.Int64  `tfsdk:"dscgctrl"`
 }
 
 type DSCGsDataSourceData struct {
-	ModuleDSCGs []ModuleDSCGsDataSourceData `tfsdk:"moduledscgs"`
+	ModuleDSCGs     []ModuleDSCGsDataSourceData `tfsdk:"moduledscgs"`
+	SkipUnavailable types.Bool                  `tfsdk:"skip_unavailable"`
 }
 
 // Metadata returns the data source type name.
@@ -49,11 +70,23 @@ func (d *DSCGsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of Module DSCGs",
 		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
 			"moduledscgs": schema.ListNestedAttribute{
 				Description: "List of module's ethernets",
 				Required:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
 						"n": schema.StringAttribute{
 							Description: "Device Name",
 							Required:    true,
@@ -153,61 +186,68 @@ func (d *DSCGsDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 	tflog.Debug(ctx, "DSCGsDataSource: get DSCG", map[string]interface{}{"queriesData": queriesData})
 
-	var moduleDSCGs []ModuleDSCGsDataSourceData
+	moduleDSCGs := make([]ModuleDSCGsDataSourceData, len(queriesData.ModuleDSCGs))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModuleDSCGs))
 
-	for _, queryData := range queriesData.ModuleDSCGs {
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModuleDSCGs), func(i int) {
+		queryData := queriesData.ModuleDSCGs[i]
 		tflog.Debug(ctx, "DSCGsDataSource Read: get DSCGs", map[string]interface{}{"queryData": queryData})
 
-		data, deviceId, err := GetResource(ctx, d.client, queryData.N.ValueString(), "resources/lineptps/"+queryData.LinePTPId.ValueString()+"/carriers/"+queryData.CarrierId.ValueString()+"/dscgs")
+		client := xrcm.NewClientWithRequester(d.client)
+		links, err := client.ListDSCGs(ctx, queryData.N.ValueString(), queryData.LinePTPId.ValueString(), queryData.CarrierId.ValueString())
 
 		if err != nil {
-			resp.Diagnostics.AddError(
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
 				"DSCGsDataSource Read: Error Get DSCGs",
 				"Read: Could not GET DSCGs, unexpected error: "+err.Error(),
 			)
+			moduleDSCGs[i] = queryData
 			return
 		}
-		tflog.Debug(ctx, "DSCGsDataSource: get DSCGs", map[string]interface{}{"data": data})
-
-		resultData := (data["data"].(map[string]interface{}))["content"].(map[string]interface{})
-		links := resultData["links"].([]interface{})
 		tflog.Debug(ctx, "DSCGsDataSource: get DSCG links", map[string]interface{}{"links": links})
-		var dscgs []DSCGResourceData
+		deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
+		var dscgs []DSCGData
 
-		for _, v := range links {
-			dscgrec := v.(map[string]interface{})
-			href := dscgrec["href"].(string)
+		for _, href := range links {
 			dscgId := href[strings.LastIndex(href, "/")+1:]
 			if len(queryData.DSCGIds) > 0 && Find(dscgId, queryData.DSCGIds) == -1 {
 				continue
 			}
 
-			data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources/"+dscgrec["href"].(string))
+			dscg, err := client.GetDSCG(ctx, queryData.N.ValueString(), href)
 			if err != nil {
-				continue
+				reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+					"DSCGsDataSource Read: Error Get DSCGs",
+					"DSCGsDataSource: Could not GET DSCG "+href+", unexpected error: "+err.Error(),
+				)
+				moduleDSCGs[i] = queryData
+				return
 			}
 
-			resultData2 := data2["data"].(map[string]interface{})
-			dscgDataRec := resultData2["content"].(map[string]interface{})
-			dscgData := DSCGResourceData{}
+			dscgData := DSCGData{}
 			dscgData.N = types.StringValue(queryData.N.ValueString())
 			dscgData.DscgId = types.StringValue(dscgId)
 			dscgData.Id = types.StringValue(queryData.N.ValueString() + href)
-			dscgData.Aid = types.StringValue(dscgDataRec["aid"].(string))
-			idleCDSCList := getBits(int(dscgDataRec["idleCDSCs"].(float64)))
-			dscgData.IdleCDSCs, _ = types.ListValue(types.Int64Type, idleCDSCList)
-			dscgData.DscgCtrl = types.Int64Value(int64(dscgDataRec["dscgCtrl"].(float64)))
+			dscgData.Aid = stringPointerValue(dscg.Aid)
+			dscgData.IdleCDSCs = bitsPointerValue(dscg.IdleCDSCs)
+			dscgData.DscgCtrl = int64PointerValue(dscg.DscgCtrl)
 			dscgData.DeviceId = types.StringValue(deviceId)
-			rxCDSCList := getBits(int(dscgDataRec["rxCDSCs"].(float64)))
-			dscgData.RxCDSCs, _ = types.ListValue(types.Int64Type, rxCDSCList)
-			txCDSCList := getBits(int(dscgDataRec["txCDSCs"].(float64)))
-			dscgData.TxCDSCs, _ = types.ListValue(types.Int64Type, txCDSCList)
+			dscgData.RxCDSCs = bitsPointerValue(dscg.RxCDSCs)
+			dscgData.TxCDSCs = bitsPointerValue(dscg.TxCDSCs)
 			dscgs = append(dscgs, dscgData)
 		}
 		tflog.Debug(ctx, "DSCGsDataSource: get carriers", map[string]interface{}{"N": queryData.N.ValueString(), "linePTPId": queryData.LinePTPId.ValueString(), "CarrierId": queryData.CarrierId.ValueString(), "module DSCG IDs": queryData.DSCGIds, "Module DSCGs": dscgs})
-		queryData.DSCGs = make([]DSCGResourceData, len(dscgs))
+		queryData.DSCGs = make([]DSCGData, len(dscgs))
 		queryData.DSCGs = dscgs
-		moduleDSCGs = append(moduleDSCGs, queryData)
+		queryData.Status = types.StringValue(moduleStatusOK)
+		moduleDSCGs[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	tflog.Debug(ctx, "DSCGsDataSource: get module DSCGs", map[string]interface{}{"DSCGs": moduleDSCGs})
 	queriesData.ModuleDSCGs = make([]ModuleDSCGsDataSourceData, len(moduleDSCGs))
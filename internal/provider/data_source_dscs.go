@@ -4,10 +4,12 @@ import (
 	"context"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -29,15 +31,35 @@ type DSCsDataSource struct {
 }
 
 type ModuleDSCsDataSourceData struct {
-	N         types.String      `tfsdk:"n"`
-	LinePTPId types.String      `tfsdk:"lineptpid"`
-	CarrierId types.String      `tfsdk:"carrierid"`
-	DSCIds    []types.String    `tfsdk:"dscids"`
-	DSCs      []DSCResourceData `tfsdk:"dscs"`
+	N         types.String   `tfsdk:"n"`
+	LinePTPId types.String   `tfsdk:"lineptpid"`
+	CarrierId types.String   `tfsdk:"carrierid"`
+	DSCIds    []types.String `tfsdk:"dscids"`
+	DSCs      []DSCData      `tfsdk:"dscs"`
+	Status    types.String   `tfsdk:"status"`
+	Error     types.String   `tfsdk:"error"`
+}
+
+// DSCData is a DSC of the data source, the attributes of xrcm_dsc the device
+// reports.
+type DSCData struct {
+	Id          types.String `tfsdk:"id"`
+	DeviceId    types.String `tfsdk:"deviceid"`
+	N           types.String `tfsdk:"n"`
+	LinePTPId   types.String `tfsdk:"lineptpid"`
+	CarrierId   types.String `tfsdk:"carrierid"`
+	Aid         types.String `tfsdk:"aid"`
+	DscId       types.String `tfsdk:"dscid"`
+	CDsc        types.Int64  `tfsdk:"cdsc"`
+	TxStatus    types.String `tfsdk:"txstatus"`
+	RxStatus    types.String `tfsdk:"rxstatus"`
+	RelativeDPO types.Int64  `tfsdk:"relativedpo"`
+	ConfigState types.String `tfsdk:"configstate"`
 }
 
 type DSCsDataSourceData struct {
-	ModuleDSCs []ModuleDSCsDataSourceData `tfsdk:"moduledscs"`
+	ModuleDSCs      []ModuleDSCsDataSourceData `tfsdk:"moduledscs"`
+	SkipUnavailable types.Bool                 `tfsdk:"skip_unavailable"`
 }
 
 // Metadata returns the data source type name.
@@ -49,11 +71,23 @@ func (d *DSCsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of Module DSCs",
 		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
 			"moduledscs": schema.ListNestedAttribute{
 				Description: "List of module's ethernets",
 				Required:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
 						"n": schema.StringAttribute{
 							Description: "Device Name",
 							Required:    true,
@@ -156,34 +190,35 @@ func (d *DSCsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 
 	tflog.Debug(ctx, "DSCsDataSource: get DSCs", map[string]interface{}{"queriesData": queriesData})
 
-	var moduleDSCs []ModuleDSCsDataSourceData
+	moduleDSCs := make([]ModuleDSCsDataSourceData, len(queriesData.ModuleDSCs))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModuleDSCs))
 
-	for _, queryData := range queriesData.ModuleDSCs {
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModuleDSCs), func(i int) {
+		queryData := queriesData.ModuleDSCs[i]
 
 		tflog.Debug(ctx, "DSCsDataSource: get dscs", map[string]interface{}{"queryData": queryData})
 
-		data, deviceId, err := GetResource(ctx, d.client, queryData.N.ValueString(), "resource-links")
+		client := xrcm.NewClientWithRequester(d.client)
+		links, err := client.ResourceLinks(ctx, queryData.N.ValueString())
 
 		if err != nil {
-			resp.Diagnostics.AddError(
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
 				"Error Read DSCsDataSource",
 				"DSCsDataSource: Could not GET DSC, unexpected error: "+err.Error(),
 			)
+			moduleDSCs[i] = queryData
 			return
 		}
 
-		tflog.Debug(ctx, "DSCsDataSource: get DSCs", map[string]interface{}{"data": data})
+		tflog.Debug(ctx, "DSCsDataSource: get DSCs", map[string]interface{}{"links": links})
+		deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
 
-		resources := data["resources"].([]interface{})
+		var dscs []DSCData
 
-		var dscs []DSCResourceData
+		for _, link := range links {
+			href := link.Href
 
-		for _, v := range resources {
-			rec := v.(map[string]interface{})
-			rsType := rec["resourceTypes"].([]interface{})
-			href := rec["href"].(string)
-
-			if rsType[0].(string) != "xr.carrier.dsc" || !strings.Contains(href, "lineptps/"+queryData.LinePTPId.ValueString()+"/carriers/"+queryData.CarrierId.ValueString()+"/") {
+			if len(link.ResourceTypes) == 0 || link.ResourceTypes[0] != "xr.carrier.dsc" || !strings.Contains(href, "lineptps/"+queryData.LinePTPId.ValueString()+"/carriers/"+queryData.CarrierId.ValueString()+"/") {
 				continue
 			}
 
@@ -193,32 +228,43 @@ func (d *DSCsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 				continue
 			}
 
-			data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources/"+rec["href"].(string))
+			dsc, err := client.GetDSC(ctx, queryData.N.ValueString(), queryData.LinePTPId.ValueString(), queryData.CarrierId.ValueString(), dscId)
 			if err != nil {
-				continue
+				reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+					"Error Read DSCsDataSource",
+					"DSCsDataSource: Could not GET DSC "+href+", unexpected error: "+err.Error(),
+				)
+				moduleDSCs[i] = queryData
+				return
 			}
 
-			resultData2 := data2["data"].(map[string]interface{})
-			dsc := resultData2["content"].(map[string]interface{})
-			dscData := DSCResourceData{}
+			dscData := DSCData{}
 			dscData.N = types.StringValue(queryData.N.ValueString())
 			dscData.DeviceId = types.StringValue(deviceId)
 			dscData.LinePTPId = types.StringValue(queryData.LinePTPId.ValueString())
 			dscData.CarrierId = types.StringValue(queryData.CarrierId.ValueString())
 			dscData.DscId = types.StringValue(dscId)
-			dscData.Aid = types.StringValue(dsc["aid"].(string))
+			dscData.Aid = stringPointerValue(dsc.Aid)
 			dscData.Id = types.StringValue(queryData.N.ValueString() + href)
-			dscData.TxStatus = types.StringValue(dsc["txStatus"].(string))
-			dscData.RxStatus = types.StringValue(dsc["rxStatus"].(string))
-			dscData.RelativeDPO = types.Int64Value(int64(dsc["relativeDPO"].(float64)))
-			dscData.CDsc = types.Int64Value(int64(dsc["cDsc"].(float64)))
-			dscData.ConfigState = types.StringValue(dsc["configState"].(string))
+			dscData.TxStatus = stringPointerValue(dsc.TxStatus)
+			dscData.RxStatus = stringPointerValue(dsc.RxStatus)
+			dscData.RelativeDPO = int64PointerValue(dsc.RelativeDPO)
+			dscData.CDsc = int64PointerValue(dsc.CDsc)
+			dscData.ConfigState = stringPointerValue(dsc.ConfigState)
 			dscs = append(dscs, dscData)
 		}
 		tflog.Debug(ctx, "dscsDataSource: get dscs", map[string]interface{}{"dscs": dscs})
-		queryData.DSCs = make([]DSCResourceData, len(dscs))
+		queryData.DSCs = make([]DSCData, len(dscs))
 		queryData.DSCs = dscs
-		moduleDSCs = append(moduleDSCs, queryData)
+		queryData.Status = types.StringValue(moduleStatusOK)
+		moduleDSCs[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	tflog.Debug(ctx, "DSCsDataSource: get module DSCs", map[string]interface{}{"DSCs": moduleDSCs})
 	queriesData.ModuleDSCs = make([]ModuleDSCsDataSourceData, len(moduleDSCs))
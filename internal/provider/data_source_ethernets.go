@@ -4,10 +4,12 @@ import (
 	"context"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -29,13 +31,31 @@ type EthernetsDataSource struct {
 }
 
 type EthernetsDataSourceData struct {
-	N           types.String           `tfsdk:"n"`
-	EthernetIds []types.String         `tfsdk:"ethernetids"`
-	Ethernets   []EthernetResourceData `tfsdk:"ethernets"`
+	N           types.String   `tfsdk:"n"`
+	EthernetIds []types.String `tfsdk:"ethernetids"`
+	Ethernets   []EthernetData `tfsdk:"ethernets"`
+	Status      types.String   `tfsdk:"status"`
+	Error       types.String   `tfsdk:"error"`
+}
+
+// EthernetData is a client port of the data source, the attributes of
+// xrcm_ethernet the device reports.
+type EthernetData struct {
+	Id          types.String `tfsdk:"id"`
+	N           types.String `tfsdk:"n"`
+	DeviceId    types.String `tfsdk:"deviceid"`
+	Aid         types.String `tfsdk:"aid"`
+	EthernetId  types.String `tfsdk:"ethernetid"`
+	FecMode     types.String `tfsdk:"fecmode"`
+	FecType     types.String `tfsdk:"fectype"`
+	PortSpeed   types.Int64  `tfsdk:"portspeed"`
+	MaxPktLen   types.Int64  `tfsdk:"maxpktlen"`
+	ConfigState types.String `tfsdk:"configstate"`
 }
 
 type ModuleEthernetsDataSourceData struct {
 	ModuleEthernets []EthernetsDataSourceData `tfsdk:"moduleethernets"`
+	SkipUnavailable types.Bool                `tfsdk:"skip_unavailable"`
 }
 
 // Metadata returns the data source type name.
@@ -47,11 +67,23 @@ func (d *EthernetsDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of ModuleEthernets",
 		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
 			"moduleethernets": schema.ListNestedAttribute{
 				Description: "List of module's ethernets",
 				Required:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
 						"n": schema.StringAttribute{
 							Description: "Device Name",
 							Required:    true,
@@ -138,34 +170,35 @@ func (d *EthernetsDataSource) Read(ctx context.Context, req datasource.ReadReque
 
 	tflog.Debug(ctx, "EthernetsDataSource: get EThernet", map[string]interface{}{"querysData": querysData})
 
-	var moduleEthernets []EthernetsDataSourceData
+	moduleEthernets := make([]EthernetsDataSourceData, len(querysData.ModuleEthernets))
+	moduleDiags := make([]diag.Diagnostics, len(querysData.ModuleEthernets))
 
-	for _, queryData := range querysData.ModuleEthernets {
+	forEachParallel(d.client.MaxConcurrency, len(querysData.ModuleEthernets), func(i int) {
+		queryData := querysData.ModuleEthernets[i]
 
 		tflog.Debug(ctx, "EthernetsDataSource: get Ehernets", map[string]interface{}{"queryData": queryData})
 
-		data, deviceId, err := GetResource(ctx, d.client, queryData.N.ValueString(), "resource-links")
+		client := xrcm.NewClientWithRequester(d.client)
+		links, err := client.ResourceLinks(ctx, queryData.N.ValueString())
 
 		if err != nil {
-			resp.Diagnostics.AddError(
+			reportModuleError(querysData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
 				"Error Read EthernetsDataSource",
 				"EthernetsDataSource: Could not GET Ethernet, unexpected error: "+err.Error(),
 			)
+			moduleEthernets[i] = queryData
 			return
 		}
 
-		tflog.Debug(ctx, "EthernetsDataSource: get Ethernets", map[string]interface{}{"data": data})
+		tflog.Debug(ctx, "EthernetsDataSource: get Ethernets", map[string]interface{}{"links": links})
+		deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
 
-		resources := data["resources"].([]interface{})
+		var ethernets []EthernetData
 
-		var ethernets []EthernetResourceData
+		for _, link := range links {
+			href := link.Href
 
-		for _, v := range resources {
-			rec := v.(map[string]interface{})
-			rsType := rec["resourceTypes"].([]interface{})
-			href := rec["href"].(string)
-
-			if rsType[0].(string) != "xr.ethernet" {
+			if len(link.ResourceTypes) == 0 || link.ResourceTypes[0] != "xr.ethernet" {
 				continue
 			}
 
@@ -175,30 +208,41 @@ func (d *EthernetsDataSource) Read(ctx context.Context, req datasource.ReadReque
 				continue
 			}
 
-			data2, err := GetResourcebyID(ctx, d.client, deviceId, "resources/"+rec["href"].(string))
+			ethernet, err := client.GetEthernet(ctx, queryData.N.ValueString(), ethernetId)
 			if err != nil {
-				continue
+				reportModuleError(querysData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+					"Error Read EthernetsDataSource",
+					"EthernetsDataSource: Could not GET ethernet "+href+", unexpected error: "+err.Error(),
+				)
+				moduleEthernets[i] = queryData
+				return
 			}
 
-			resultData2 := data2["data"].(map[string]interface{})
-			ethernet := resultData2["content"].(map[string]interface{})
-			ethernetData := EthernetResourceData{}
+			ethernetData := EthernetData{}
 			ethernetData.N = types.StringValue(queryData.N.ValueString())
 			ethernetData.DeviceId = types.StringValue(deviceId)
 			ethernetData.EthernetId = types.StringValue(ethernetId)
-			ethernetData.Aid = types.StringValue(ethernet["aid"].(string))
+			ethernetData.Aid = stringPointerValue(ethernet.Aid)
 			ethernetData.Id = types.StringValue(queryData.N.ValueString() + href)
-			ethernetData.FecType = types.StringValue(ethernet["fecType"].(string))
-			ethernetData.FecMode = types.StringValue(ethernet["fecMode"].(string))
-			ethernetData.PortSpeed = types.Int64Value(int64(ethernet["portSpeed"].(float64)))
-			ethernetData.MaxPktLen = types.Int64Value(int64(ethernet["maxPktLen"].(float64)))
-			ethernetData.ConfigState = types.StringValue(ethernet["configState"].(string))
+			ethernetData.FecType = stringPointerValue(ethernet.FecType)
+			ethernetData.FecMode = stringPointerValue(ethernet.FecMode)
+			ethernetData.PortSpeed = int64PointerValue(ethernet.PortSpeed)
+			ethernetData.MaxPktLen = int64PointerValue(ethernet.MaxPktLen)
+			ethernetData.ConfigState = stringPointerValue(ethernet.ConfigState)
 			ethernets = append(ethernets, ethernetData)
 		}
 		tflog.Debug(ctx, "ethernetsDataSource: get ethernets", map[string]interface{}{"ethernets": ethernets})
-		queryData.Ethernets = make([]EthernetResourceData, len(ethernets))
+		queryData.Ethernets = make([]EthernetData, len(ethernets))
 		queryData.Ethernets = ethernets
-		moduleEthernets = append(moduleEthernets, queryData)
+		queryData.Status = types.StringValue(moduleStatusOK)
+		moduleEthernets[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	tflog.Debug(ctx, "ethernetsDataSource: get ethernets", map[string]interface{}{"Module ethernets": moduleEthernets})
 	querysData.ModuleEthernets = make([]EthernetsDataSourceData, len(moduleEthernets))
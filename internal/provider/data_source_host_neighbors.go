@@ -2,9 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -147,7 +147,7 @@ func (d *HostNeighborsDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	tflog.Debug(ctx, "HostNeighborDataSource Read: get HostNeighbor", map[string]interface{}{"req": req})
 
-	body, deviceId, err := d.client.ExecuteDeviceHttpCommand(queryData.N.ValueString(), "GET", "resources/ethernets/"+queryData.EthernetId.ValueString()+"/host-neighbors", nil)
+	hostNeighbors, err := xrcm.NewClientWithRequester(d.client).GetHostNeighbors(ctx, queryData.N.ValueString(), queryData.EthernetId.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -157,40 +157,23 @@ func (d *HostNeighborsDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
-	data := make(map[string]interface{})
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"HostNeighborDataSource Read: Error Read HostNeighbor",
-			"Read: Could not Unmarshal HostNeighbor, unexpected error: "+err.Error(),
-		)
-		return
-	}
-
-	var resultData = make(map[string]interface{})
-	var content = map[string]interface{}{}
-	var neighbors = []interface{}{}
-
-	resultData = data["data"].(map[string]interface{})
-	tflog.Debug(ctx, "HostNeighborDataSource: get Hostneighbors", map[string]interface{}{"resultData": resultData})
+	tflog.Debug(ctx, "HostNeighborDataSource: get Hostneighbors", map[string]interface{}{"hostNeighbors": hostNeighbors})
+	deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
 	queryData.Neighbors = make([]HostNeighborData, 0)
 	queryData.DeviceId = types.StringValue(deviceId)
-	content = resultData["content"].(map[string]interface{})
-	queryData.Aid = types.StringValue(content["aid"].(string))
-	neighbors = content["neighbors"].([]interface{})
-	for _, n := range neighbors {
-		neighbor := n.(map[string]interface{})
+	queryData.Aid = types.StringValue(hostNeighbors.Aid)
+	for _, neighbor := range hostNeighbors.Neighbors {
 		hostNeighborData := HostNeighborData{}
-		hostNeighborData.LocalPortSourceMAC = types.StringValue(neighbor["localPortSourceMAC"].(string))
-		hostNeighborData.ChassisIdSubtype = types.StringValue(neighbor["chassisIdSubtype"].(string))
-		hostNeighborData.ChassisId = types.StringValue(neighbor["chassisId"].(string))
-		hostNeighborData.PortIdSubtype = types.StringValue(neighbor["portIdSubtype"].(string))
-		hostNeighborData.PortId = types.StringValue(neighbor["portId"].(string))
-		hostNeighborData.PortDescr = types.StringValue(neighbor["portDescr"].(string))
-		hostNeighborData.SysName = types.StringValue(neighbor["sysName"].(string))
-		hostNeighborData.SysDescr = types.StringValue(neighbor["sysDescr"].(string))
-		hostNeighborData.SysTTL = types.Int64Value(int64(neighbor["sysTTL"].(float64)))
-		hostNeighborData.LldpPdu = types.StringValue(neighbor["lldpPDU"].(string))
+		hostNeighborData.LocalPortSourceMAC = types.StringValue(neighbor.LocalPortSourceMAC)
+		hostNeighborData.ChassisIdSubtype = types.StringValue(neighbor.ChassisIdSubtype)
+		hostNeighborData.ChassisId = types.StringValue(neighbor.ChassisId)
+		hostNeighborData.PortIdSubtype = types.StringValue(neighbor.PortIdSubtype)
+		hostNeighborData.PortId = types.StringValue(neighbor.PortId)
+		hostNeighborData.PortDescr = types.StringValue(neighbor.PortDescr)
+		hostNeighborData.SysName = types.StringValue(neighbor.SysName)
+		hostNeighborData.SysDescr = types.StringValue(neighbor.SysDescr)
+		hostNeighborData.SysTTL = types.Int64Value(neighbor.SysTTL)
+		hostNeighborData.LldpPdu = types.StringValue(neighbor.LldpPDU)
 
 		queryData.Neighbors = append(queryData.Neighbors, hostNeighborData)
 	}
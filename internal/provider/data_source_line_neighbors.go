@@ -2,9 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -149,7 +149,7 @@ func (d *LineNeighborDataSource) Read(ctx context.Context, req datasource.ReadRe
 
 	tflog.Debug(ctx, "LineNeighborDataSource Read: get LineNeighbor", map[string]interface{}{"req": req})
 
-	body, deviceId, err := d.client.ExecuteDeviceHttpCommand(queryData.N.ValueString(), "GET", "resources/lineptps/"+queryData.LinePTPId.ValueString()+"/neighbors", nil)
+	lineNeighbors, err := xrcm.NewClientWithRequester(d.client).GetLineNeighbors(ctx, queryData.N.ValueString(), queryData.LinePTPId.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -159,54 +159,31 @@ func (d *LineNeighborDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	data := make(map[string]interface{})
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"LineNeighborDataSource Read: Error Read LineNeighbor",
-			"Read: Could not Unmarshal LineNeighbor, unexpected error: "+err.Error(),
-		)
-		return
-	}
-
-	resultData := data["data"].(map[string]interface{})
-	content := resultData["content"].(map[string]interface{})
-	tflog.Debug(ctx, "Read: get DSCGs", map[string]interface{}{"content": content})
-	var discoveredneighbors []interface{}
-	if content["discoveredneighbors"] != nil {
-		discoveredneighbors = content["discoveredneighbors"].([]interface{})
-	}
-	var controlplaneneighbors []interface{}
-	if content["controlplaneneighbors"] != nil {
-		controlplaneneighbors = content["controlplaneneighbors"].([]interface{})
-	}
-
+	deviceId, _ := d.client.GetDeviceIdFromName(queryData.N.ValueString())
 	queryData.DiscoveredNeighbors = make([]DiscoveredNeighborData, 0)
 	queryData.ControlPlaneNeighbors = make([]ControlPlaneNeighborData, 0)
 	queryData.DeviceId = types.StringValue(deviceId)
 
-	for _, v := range discoveredneighbors {
-		neighbor := v.(map[string]interface{})
+	for _, neighbor := range lineNeighbors.DiscoveredNeighbors {
 		neighborD := DiscoveredNeighborData{}
-		neighborD.MacAddress = types.StringValue(neighbor["macAddress"].(string))
-		neighborD.CurrentRole = types.StringValue(neighbor["currentRole"].(string))
-		neighborD.DiscoveredTime = types.StringValue(neighbor["discoveredTime"].(string))
-		neighborD.ConstellationFrequency = types.StringValue(neighbor["constellationFrequency"].(string))
+		neighborD.MacAddress = types.StringValue(neighbor.MacAddress)
+		neighborD.CurrentRole = types.StringValue(neighbor.CurrentRole)
+		neighborD.DiscoveredTime = types.StringValue(neighbor.DiscoveredTime)
+		neighborD.ConstellationFrequency = types.StringValue(neighbor.ConstellationFrequency)
 		queryData.DiscoveredNeighbors = append(queryData.DiscoveredNeighbors, neighborD)
 	}
 
-	for _, v := range controlplaneneighbors {
-		neighbor := v.(map[string]interface{})
+	for _, neighbor := range lineNeighbors.ControlPlaneNeighbors {
 		neighborC := ControlPlaneNeighborData{}
-		neighborC.MacAddress = types.StringValue(neighbor["macAddress"].(string))
-		neighborC.CurrentRole = types.StringValue(neighbor["currentRole"].(string))
-		neighborC.ConstellationFrequency = types.StringValue(neighbor["constellationFrequency"].(string))
-		neighborC.ConState = types.StringValue(neighbor["conState"].(string))
-		neighborC.LastConStateChange = types.StringValue(neighbor["lastConStateChange"].(string))
+		neighborC.MacAddress = types.StringValue(neighbor.MacAddress)
+		neighborC.CurrentRole = types.StringValue(neighbor.CurrentRole)
+		neighborC.ConstellationFrequency = types.StringValue(neighbor.ConstellationFrequency)
+		neighborC.ConState = types.StringValue(neighbor.ConState)
+		neighborC.LastConStateChange = types.StringValue(neighbor.LastConStateChange)
 		queryData.ControlPlaneNeighbors = append(queryData.ControlPlaneNeighbors, neighborC)
 	}
 
-	tflog.Debug(ctx, "LineNeighborDataSource Read:", map[string]interface{}{"discoveredneighbors": discoveredneighbors, "controlplaneneighbors": controlplaneneighbors})
+	tflog.Debug(ctx, "LineNeighborDataSource Read:", map[string]interface{}{"lineNeighbors": lineNeighbors})
 	diags = resp.State.Set(ctx, &queryData)
 	resp.Diagnostics.Append(diags...)
 }
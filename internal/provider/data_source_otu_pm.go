@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &OTUPMDataSource{}
+	_ datasource.DataSourceWithConfigure = &OTUPMDataSource{}
+)
+
+// NewOTUPMDataSource is a helper function to simplify the provider implementation.
+func NewOTUPMDataSource() datasource.DataSource {
+	return &OTUPMDataSource{}
+}
+
+// OTUPMDataSource reads the current PM of the otus.
+type OTUPMDataSource struct {
+	client *xrcm_pf.Client
+}
+
+type OTUPMData struct {
+	Id      types.String           `tfsdk:"id"`
+	OtuId   types.String           `tfsdk:"otuid"`
+	Metrics map[string]PMValueData `tfsdk:"metrics"`
+}
+
+type OTUsPMData struct {
+	N        types.String   `tfsdk:"n"`
+	DeviceId types.String   `tfsdk:"deviceid"`
+	OtuIds   []types.String `tfsdk:"otuids"`
+	PMs      []OTUPMData    `tfsdk:"pms"`
+	Status   types.String   `tfsdk:"status"`
+	Error    types.String   `tfsdk:"error"`
+}
+
+type OTUPMDataSourceData struct {
+	ModulePMs       []OTUsPMData `tfsdk:"modulepms"`
+	SkipUnavailable types.Bool   `tfsdk:"skip_unavailable"`
+}
+
+// Metadata returns the data source type name.
+func (d *OTUPMDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_otu_pm"
+}
+
+func (d *OTUPMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the current performance monitoring of Modules' otus",
+		Attributes: map[string]schema.Attribute{
+			"skip_unavailable": schema.BoolAttribute{
+				Description: "Return the modules that could be read instead of failing, with status and error set on the others",
+				Optional:    true,
+			},
+			"modulepms": schema.ListNestedAttribute{
+				Description: "List of modules' otus PM",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"status": schema.StringAttribute{
+							Description: "OK, or ERROR if the module could not be read",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Why the module could not be read",
+							Computed:    true,
+						},
+						"n": schema.StringAttribute{
+							Description: "Device Name",
+							Required:    true,
+						},
+						"deviceid": schema.StringAttribute{
+							Description: "device id",
+							Computed:    true,
+						},
+						"otuids": schema.ListAttribute{
+							Description: "List of otu ID, default all the otus of the module",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"pms": schema.ListNestedAttribute{
+							Description: "List of otus PM",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.StringAttribute{
+										Description: "Identifier of the OTU, device name and href.",
+										Computed:    true,
+									},
+									"otuid": schema.StringAttribute{
+										Description: "otu id",
+										Computed:    true,
+									},
+									"metrics": pmMetricsAttribute("PM values by name as reported by the module, e.g. preFecBer, qFactor, fecCorrected, fecUncorrected"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *OTUPMDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*xrcm_pf.Client)
+}
+
+func (d *OTUPMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	queriesData := OTUPMDataSourceData{}
+
+	diags := req.Config.Get(ctx, &queriesData)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "OTUPMDataSource: get OTUs PM", map[string]interface{}{"queriesData": queriesData})
+
+	modulePMs := make([]OTUsPMData, len(queriesData.ModulePMs))
+	moduleDiags := make([]diag.Diagnostics, len(queriesData.ModulePMs))
+
+	forEachParallel(d.client.MaxConcurrency, len(queriesData.ModulePMs), func(i int) {
+		queryData := queriesData.ModulePMs[i]
+
+		pms, deviceId, err := readPMs(ctx, d.client, queryData.N.ValueString(), "xr.otu", "/otus/", queryData.OtuIds)
+		queryData.DeviceId = types.StringValue(deviceId)
+		if err != nil {
+			reportModuleError(queriesData.SkipUnavailable.ValueBool(), &moduleDiags[i], &queryData.Status, &queryData.Error,
+				"Error Read OTUPMDataSource",
+				"OTUPMDataSource: Could not GET OTUs PM, unexpected error: "+err.Error(),
+			)
+			modulePMs[i] = queryData
+			return
+		}
+
+		queryData.PMs = make([]OTUPMData, 0, len(pms))
+		for _, p := range pms {
+			queryData.PMs = append(queryData.PMs, OTUPMData{
+				Id:      types.StringValue(queryData.N.ValueString() + p.href),
+				OtuId:   types.StringValue(p.resourceId),
+				Metrics: p.metrics,
+			})
+		}
+		queryData.Status = types.StringValue(moduleStatusOK)
+		modulePMs[i] = queryData
+	})
+
+	for _, moduleDiag := range moduleDiags {
+		resp.Diagnostics.Append(moduleDiag...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "OTUPMDataSource: get module PMs", map[string]interface{}{"modulepms": modulePMs})
+	queriesData.ModulePMs = modulePMs
+	diags = resp.State.Set(ctx, &queriesData)
+	resp.Diagnostics.Append(diags...)
+}
@@ -2,11 +2,14 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
-	"io/ioutil"
-	"os"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/fujiwara/tfstate-lookup/tfstate"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -36,7 +39,12 @@ type DeviceID struct {
 }
 
 type DeviceIdsData struct {
-	DeviceIds []DeviceID `tfsdk:"deviceids"`
+	DeviceIds     []DeviceID   `tfsdk:"deviceids"`
+	State         types.String `tfsdk:"state"`
+	Address       types.String `tfsdk:"address"`
+	Attribute     types.String `tfsdk:"attribute"`
+	NameAttribute types.String `tfsdk:"name_attribute"`
+	IdAttribute   types.String `tfsdk:"id_attribute"`
 }
 
 // Metadata returns the data source type name.
@@ -48,6 +56,26 @@ func (d *DeviceIdsDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of Device Ids.",
 		Attributes: map[string]schema.Attribute{
+			"state": schema.StringAttribute{
+				Description: "Terraform state, a file path or the URL of its backend: s3://bucket/key, gs://bucket/key, azurerm://resource_group/account/container/key, remote://app.terraform.io/organization/workspace, http(s)://, default terraform.tfstate of the working directory",
+				Optional:    true,
+			},
+			"address": schema.StringAttribute{
+				Description: "Address of the resource, data source or output holding the devices, e.g. data.xrcm_detaildevices.devices, module.net.output.devices, default every xrcm_detaildevices",
+				Optional:    true,
+			},
+			"attribute": schema.StringAttribute{
+				Description: "Path of the devices in the resource, e.g. devices, empty for the resource itself, default devices",
+				Optional:    true,
+			},
+			"name_attribute": schema.StringAttribute{
+				Description: "Attribute of the device name in each device, default name",
+				Optional:    true,
+			},
+			"id_attribute": schema.StringAttribute{
+				Description: "Attribute of the device id in each device, default deviceid",
+				Optional:    true,
+			},
 			"deviceids": schema.ListNestedAttribute{
 				Description: "List of device ids.",
 				Required:    true,
@@ -88,58 +116,41 @@ func (d *DeviceIdsDataSource) Read(ctx context.Context, req datasource.ReadReque
 
 	tflog.Debug(ctx, "DeviceIdsDataSource: get Check, request", map[string]interface{}{"IdsData": deviceIdsData})
 
-	path, _ := os.Getwd()
+	location := "terraform.tfstate"
+	if !deviceIdsData.State.IsNull() {
+		location = deviceIdsData.State.ValueString()
+	}
 
-	tflog.Debug(ctx, "DeviceIdsDataSource: ", map[string]interface{}{"path": path + "/terraform.tfstate"})
+	tflog.Debug(ctx, "DeviceIdsDataSource: ", map[string]interface{}{"state": location})
 
-	// Open our jsonFile
-	jsonFile, err := os.Open(path + "/terraform.tfstate")
-	// if we os.Open returns an error then handle it
+	state, err := GetTFState(ctx, location)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"DeviceIdsDataSource Failed!!", " Can not open TF state file: "+path+"/terraform.tfstate",
+			"DeviceIdsDataSource Failed!!", " Can not read TF state "+location+": "+err.Error(),
 		)
 		return
 	}
-	// defer the closing of our jsonFile so that we can parse it later on
-	defer jsonFile.Close()
-
-	byteValue, _ := ioutil.ReadAll(jsonFile)
-
-	var result map[string]interface{}
-	json.Unmarshal([]byte(byteValue), &result)
 
-	//tflog.Debug(ctx, "DeviceIdsDataSource: ", "state", result)
-
-	if result == nil {
-		tflog.Debug(ctx, "DeviceIdsDataSource: No devices ids in tf state file")
-		resp.State.Set(ctx, &deviceIdsData)
-		return
+	attribute := "devices"
+	if !deviceIdsData.Attribute.IsNull() {
+		attribute = deviceIdsData.Attribute.ValueString()
+	}
+	nameAttribute, idAttribute := "name", "deviceid"
+	if !deviceIdsData.NameAttribute.IsNull() {
+		nameAttribute = deviceIdsData.NameAttribute.ValueString()
+	}
+	if !deviceIdsData.IdAttribute.IsNull() {
+		idAttribute = deviceIdsData.IdAttribute.ValueString()
 	}
 
-	var deviceIds []DeviceID
-
-	for _, res := range result["resources"].([]interface{}) {
-		var resource = res.(map[string]interface{})
-		if resource["type"].(string) != "xrcm_detaildevices" {
-			continue
-		}
-		tflog.Debug(ctx, "DeviceIdsDataSource: res", map[string]interface{}{"res": res})
-		for _, inst := range resource["instances"].([]interface{}) {
-			var instance = inst.(map[string]interface{})
-			tflog.Debug(ctx, "DeviceIdsDataSource: instance", map[string]interface{}{"instance": instance})
-			for _, dev := range instance["attributes"].(map[string]interface{})["devices"].([]interface{}) {
-				var device = dev.(map[string]interface{})
-				tflog.Debug(ctx, "DeviceIdsDataSource: device", map[string]interface{}{"device": device})
-				deviceId := DeviceID{}
-				deviceId.N = types.StringValue(device["name"].(string))
-				deviceId.Id = types.StringValue(device["deviceid"].(string))
-				deviceIds = append(deviceIds, deviceId)
-			}
-		}
+	deviceIds, err := tfstateDevices(ctx, state, deviceIdsData.Address.ValueString(), attribute, nameAttribute, idAttribute)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"DeviceIdsDataSource Failed!!", " Can not lookup devices in TF state "+location+": "+err.Error(),
+		)
+		return
 	}
 
-	deviceIdsData.DeviceIds = make([]DeviceID, len(deviceIds))
 	deviceIdsData.DeviceIds = deviceIds
 	tflog.Debug(ctx, "DeviceIdsDataSource: get devices' ids", map[string]interface{}{"device IDs": deviceIds})
 
@@ -149,3 +160,82 @@ func (d *DeviceIdsDataSource) Read(ctx context.Context, req datasource.ReadReque
 	tflog.Debug(ctx, "DeviceIdsDataSource Read: get devices", map[string]interface{}{"# Device IDs": len(deviceIds)})
 
 }
+
+// tfstateDevices returns the devices of the attribute of address in a state,
+// or of every xrcm_detaildevices data source without an address.
+func tfstateDevices(ctx context.Context, state *tfstate.TFState, address string, attribute string, nameAttribute string, idAttribute string) ([]DeviceID, error) {
+	var addresses []string
+	if len(address) > 0 {
+		addresses = []string{address}
+	} else {
+		names, _ := state.List()
+		for _, name := range names {
+			if strings.HasPrefix(name, "data.xrcm_detaildevices.") || strings.Contains(name, ".data.xrcm_detaildevices.") {
+				addresses = append(addresses, name)
+			}
+		}
+	}
+
+	var deviceIds []DeviceID
+	for _, a := range addresses {
+		key := a
+		if len(attribute) > 0 {
+			key = a + "." + attribute
+		}
+		value, err := state.Lookup(key)
+		if err != nil || value.Value == nil {
+			if len(address) == 0 {
+				tflog.Debug(ctx, "tfstateDevices: no devices", map[string]interface{}{"key": key})
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			return nil, errors.New(key + ": not found")
+		}
+		tflog.Debug(ctx, "tfstateDevices: devices", map[string]interface{}{"key": key, "devices": value.Value})
+		deviceIds = append(deviceIds, tfstateDeviceIds(value.Value, nameAttribute, idAttribute)...)
+	}
+	return deviceIds, nil
+}
+
+// tfstateDeviceIds returns the name and id pairs of devices in a state value:
+// a list of devices, a map of devices by name, or a map of ids by name.
+func tfstateDeviceIds(value interface{}, nameAttribute string, idAttribute string) []DeviceID {
+	var deviceIds []DeviceID
+	device := func(name string, v interface{}) {
+		switch d := v.(type) {
+		case string:
+			deviceIds = append(deviceIds, DeviceID{N: types.StringValue(name), Id: types.StringValue(d)})
+		case map[string]interface{}:
+			if n, ok := d[nameAttribute].(string); ok {
+				name = n
+			}
+			id, ok := d[idAttribute].(string)
+			if ok && len(name) > 0 {
+				deviceIds = append(deviceIds, DeviceID{N: types.StringValue(name), Id: types.StringValue(id)})
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, d := range v {
+			device("", d)
+		}
+	case map[string]interface{}:
+		if _, ok := v[idAttribute]; ok {
+			device("", v)
+			break
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			device(name, v[name])
+		}
+	}
+	return deviceIds
+}
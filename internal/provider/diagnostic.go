@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// diagnosticCleared is the value of each diagnostic setting when it is off,
+// no loopback nor PRBS.
+var diagnosticCleared = map[string]interface{}{
+	"termLB":      "disabled",
+	"facLB":       "disabled",
+	"facPRBSGen":  false,
+	"facPRBSMon":  false,
+	"termPRBSGen": false,
+}
+
+// diagnosticRevertTimeout is how long revertDiagnostic polls the device for
+// the diagnostic to clear, every diagnosticRevertPollInterval.
+var (
+	diagnosticRevertTimeout      = time.Minute
+	diagnosticRevertPollInterval = 5 * time.Second
+)
+
+// maxAutoRevertAfter bounds auto_revert_after, apply holds for all of it.
+const maxAutoRevertAfter = time.Hour
+
+// diagnosticClient is the part of *xrcm_pf.Client reverting diagnostics.
+type diagnosticClient interface {
+	ExecuteDeviceHttpCommand(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error)
+	ExecuteDeviceHttpCommandNoCache(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error)
+}
+
+// diagnosticEnabled returns the diagnostic settings of values that are on.
+func diagnosticEnabled(values map[string]interface{}) []string {
+	var enabled []string
+	for k, cleared := range diagnosticCleared {
+		v, ok := values[k]
+		if !ok {
+			continue
+		}
+		if s, ok := v.(string); ok && strings.EqualFold(s, cleared.(string)) {
+			continue
+		}
+		if v == cleared {
+			continue
+		}
+		enabled = append(enabled, k)
+	}
+	return enabled
+}
+
+// diagnosticClearedOf returns the cleared values of the diagnostic settings
+// of cmd.
+func diagnosticClearedOf(cmd map[string]interface{}) map[string]interface{} {
+	var clear = make(map[string]interface{})
+	for k := range cmd {
+		if cleared, ok := diagnosticCleared[k]; ok {
+			clear[k] = cleared
+		}
+	}
+	return clear
+}
+
+// revertDiagnostic turns off, at uri, the diagnostic settings of cmd and
+// polls the device until it cleared them, for diagnosticRevertTimeout. A
+// diagnostic that no longer exists is cleared. It returns whether the
+// diagnostic is off.
+func revertDiagnostic(ctx context.Context, client diagnosticClient, deviceName string, uri string, cmd map[string]interface{}, diags *diag.Diagnostics) bool {
+	clear := diagnosticClearedOf(cmd)
+	if len(clear) == 0 {
+		return true
+	}
+
+	rb, err := json.Marshal(clear)
+	if err != nil {
+		diags.AddError(
+			"revertDiagnostic: Error Revert Diagnostic",
+			"Revert: Could not Revert Diagnostic, unexpected error: "+err.Error(),
+		)
+		return false
+	}
+
+	tflog.Debug(ctx, "revertDiagnostic: ", map[string]interface{}{"Device": deviceName, "URL": uri, "cmd": clear})
+
+	_, _, err = client.ExecuteDeviceHttpCommand(deviceName, "PUT", uri, rb)
+	if err != nil {
+		if strings.Contains(err.Error(), "status: 404") {
+			return true
+		}
+		diags.AddError(
+			"revertDiagnostic: Error Revert Diagnostic",
+			"Revert: Could not Revert Diagnostic "+uri+" on device "+deviceName+", unexpected error: "+err.Error(),
+		)
+		return false
+	}
+
+	deadline := time.Now().Add(diagnosticRevertTimeout)
+	for {
+		enabled, err := deviceDiagnosticEnabled(ctx, client, deviceName, uri)
+		if err != nil {
+			diags.AddError(
+				"revertDiagnostic: Error Revert Diagnostic",
+				"Revert: Could not Get Diagnostic "+uri+" on device "+deviceName+", unexpected error: "+err.Error(),
+			)
+			return false
+		}
+		if len(enabled) == 0 {
+			return true
+		}
+		if !time.Now().Add(diagnosticRevertPollInterval).Before(deadline) {
+			diags.AddError(
+				"revertDiagnostic: Error Revert Diagnostic",
+				"Revert: Device "+deviceName+" did not clear "+strings.Join(enabled, ", ")+" of "+uri+" after "+diagnosticRevertTimeout.String(),
+			)
+			return false
+		}
+
+		tflog.Debug(ctx, "revertDiagnostic: waiting for the device to clear", map[string]interface{}{"Device": deviceName, "URL": uri, "enabled": enabled})
+		timer := time.NewTimer(diagnosticRevertPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			diags.AddError(
+				"revertDiagnostic: Error Revert Diagnostic",
+				"Revert: Cancelled before the device cleared "+strings.Join(enabled, ", ")+" of "+uri+" on device "+deviceName+": "+ctx.Err().Error(),
+			)
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// parseAutoRevertAfter parses auto_revert_after, a positive duration of at
+// most maxAutoRevertAfter.
+func parseAutoRevertAfter(value string) (time.Duration, error) {
+	after, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if after <= 0 || after > maxAutoRevertAfter {
+		return 0, fmt.Errorf("%s is not between 0 and %s", value, maxAutoRevertAfter)
+	}
+	return after, nil
+}
+
+// validateAutoRevertAfter fails the plan of an auto_revert_after that is not
+// a duration of at most maxAutoRevertAfter, before apply turns the diagnostic
+// on.
+func validateAutoRevertAfter(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var autoRevertAfter types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auto_revert_after"), &autoRevertAfter)...)
+	if autoRevertAfter.IsNull() || autoRevertAfter.IsUnknown() {
+		return
+	}
+	if _, err := parseAutoRevertAfter(autoRevertAfter.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auto_revert_after"),
+			"Invalid Auto Revert After",
+			"auto_revert_after must be a duration of at most "+maxAutoRevertAfter.String()+", e.g. 10m: "+err.Error(),
+		)
+	}
+}
+
+// awaitDiagnosticRevert waits autoRevertAfter once cmd turned diagnostics on,
+// then checks that the device cleared them, e.g. at the end of their
+// duration, and reverts them itself if it did not. The state keeps the
+// planned settings, the next read reports them cleared.
+func awaitDiagnosticRevert(ctx context.Context, client diagnosticClient, deviceName string, uri string, cmd map[string]interface{}, autoRevertAfter types.String, diags *diag.Diagnostics) {
+	if autoRevertAfter.IsNull() || len(diagnosticEnabled(cmd)) == 0 {
+		return
+	}
+	after, err := parseAutoRevertAfter(autoRevertAfter.ValueString())
+	if err != nil {
+		diags.AddError(
+			"awaitDiagnosticRevert: Error Auto Revert Diagnostic",
+			"Revert: Could not parse auto_revert_after, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "awaitDiagnosticRevert: waiting", map[string]interface{}{"Device": deviceName, "URL": uri, "auto_revert_after": after.String()})
+
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		diags.AddError(
+			"awaitDiagnosticRevert: Error Auto Revert Diagnostic",
+			"Revert: Cancelled before auto_revert_after, the diagnostic "+uri+" on device "+deviceName+" may still be on: "+ctx.Err().Error(),
+		)
+		return
+	case <-timer.C:
+	}
+
+	enabled, err := deviceDiagnosticEnabled(ctx, client, deviceName, uri)
+	if err != nil {
+		diags.AddError(
+			"awaitDiagnosticRevert: Error Auto Revert Diagnostic",
+			"Revert: Could not Get Diagnostic "+uri+" on device "+deviceName+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if len(enabled) == 0 {
+		tflog.Info(ctx, "awaitDiagnosticRevert: cleared by the device", map[string]interface{}{"Device": deviceName, "URL": uri})
+		return
+	}
+
+	tflog.Warn(ctx, "awaitDiagnosticRevert: not cleared by the device, reverting", map[string]interface{}{"Device": deviceName, "URL": uri, "enabled": enabled})
+	revertDiagnostic(ctx, client, deviceName, uri, cmd, diags)
+}
+
+// deviceDiagnosticEnabled returns the diagnostic settings that are on in the
+// device diagnostic at uri, read past the response cache.
+func deviceDiagnosticEnabled(ctx context.Context, client diagnosticClient, deviceName string, uri string) ([]string, error) {
+	body, _, err := client.ExecuteDeviceHttpCommandNoCache(deviceName, "GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, content, err := getResourceIdNContent(body)
+	if content == nil {
+		if err == nil {
+			err = errors.New("no content in " + string(body))
+		}
+		return nil, err
+	}
+	return diagnosticEnabled(content), nil
+}
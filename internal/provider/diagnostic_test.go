@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fakeDiagnosticClient records the PUTs of a diagnostic and answers each of
+// its GETs with the next of its responses, the last one once they are used
+// up.
+type fakeDiagnosticClient struct {
+	putErr    error
+	puts      []string
+	responses []string
+	gets      int
+}
+
+func (c *fakeDiagnosticClient) ExecuteDeviceHttpCommand(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error) {
+	if command != "PUT" {
+		return nil, "", errors.New("unexpected command " + command + " " + commanduri)
+	}
+	c.puts = append(c.puts, string(commandBody))
+	if c.putErr != nil {
+		return nil, "", c.putErr
+	}
+	return []byte(`{}`), "id-" + devicename, nil
+}
+
+func (c *fakeDiagnosticClient) ExecuteDeviceHttpCommandNoCache(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error) {
+	if len(c.responses) == 0 {
+		return nil, "", errors.New("unexpected command " + command + " " + commanduri)
+	}
+	i := c.gets
+	if i >= len(c.responses) {
+		i = len(c.responses) - 1
+	}
+	c.gets++
+	return []byte(c.responses[i]), "id-" + devicename, nil
+}
+
+const (
+	diagnosticOn  = `{"data":{"resourceId":{"href":"/ethernets/1/diagnostic"},"content":{"termLB":"enabled","facPRBSGen":true}}}`
+	diagnosticOff = `{"data":{"resourceId":{"href":"/ethernets/1/diagnostic"},"content":{"termLB":"disabled","facPRBSGen":false}}}`
+)
+
+// shortDiagnosticRevert makes revertDiagnostic poll every millisecond for
+// timeout during a test.
+func shortDiagnosticRevert(t *testing.T, timeout time.Duration) {
+	savedTimeout, savedInterval := diagnosticRevertTimeout, diagnosticRevertPollInterval
+	diagnosticRevertTimeout, diagnosticRevertPollInterval = timeout, time.Millisecond
+	t.Cleanup(func() {
+		diagnosticRevertTimeout, diagnosticRevertPollInterval = savedTimeout, savedInterval
+	})
+}
+
+func TestDiagnosticClearedOf(t *testing.T) {
+	cmd := map[string]interface{}{"termLB": "enabled", "termLBDuration": int64(10), "facPRBSGen": true}
+	want := map[string]interface{}{"termLB": "disabled", "facPRBSGen": false}
+
+	if got := diagnosticClearedOf(cmd); !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnosticClearedOf() = %v, want %v", got, want)
+	}
+	if got := diagnosticClearedOf(map[string]interface{}{"termLBDuration": int64(10)}); len(got) != 0 {
+		t.Errorf("diagnosticClearedOf() of no setting = %v, want none", got)
+	}
+}
+
+func TestRevertDiagnostic(t *testing.T) {
+	shortDiagnosticRevert(t, 50*time.Millisecond)
+	cmd := map[string]interface{}{"termLB": "enabled", "facPRBSGen": true}
+	clear := `{"facPRBSGen":false,"termLB":"disabled"}`
+
+	tests := []struct {
+		name      string
+		cmd       map[string]interface{}
+		client    *fakeDiagnosticClient
+		want      bool
+		wantPuts  []string
+		wantGets  int
+		wantError bool
+	}{
+		{"cleared", cmd, &fakeDiagnosticClient{responses: []string{diagnosticOff}}, true, []string{clear}, 1, false},
+		{"cleared after a poll", cmd, &fakeDiagnosticClient{responses: []string{diagnosticOn, diagnosticOff}}, true, []string{clear}, 2, false},
+		{"never cleared", cmd, &fakeDiagnosticClient{responses: []string{diagnosticOn}}, false, []string{clear}, -1, true},
+		{"no content", cmd, &fakeDiagnosticClient{responses: []string{`{}`}}, false, []string{clear}, 1, true},
+		{"gone", cmd, &fakeDiagnosticClient{putErr: errors.New("status: 404")}, true, []string{clear}, 0, false},
+		{"put failed", cmd, &fakeDiagnosticClient{putErr: errors.New("status: 500")}, false, []string{clear}, 0, true},
+		{"no setting", map[string]interface{}{"termLBDuration": int64(10)}, &fakeDiagnosticClient{}, true, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := revertDiagnostic(context.Background(), tt.client, "xr-hub", "resources/ethernets/1/diagnostic", tt.cmd, &diags)
+			if got != tt.want || diags.HasError() != tt.wantError {
+				t.Fatalf("revertDiagnostic() = %v, errors = %v, want %v, error %v", got, diags, tt.want, tt.wantError)
+			}
+			if !reflect.DeepEqual(tt.client.puts, tt.wantPuts) {
+				t.Errorf("revertDiagnostic() PUTs = %v, want %v", tt.client.puts, tt.wantPuts)
+			}
+			if tt.wantGets >= 0 && tt.client.gets != tt.wantGets {
+				t.Errorf("revertDiagnostic() sent %d GETs, want %d", tt.client.gets, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestAwaitDiagnosticRevert(t *testing.T) {
+	shortDiagnosticRevert(t, 50*time.Millisecond)
+	cmd := map[string]interface{}{"termLB": "enabled", "facPRBSGen": true}
+
+	tests := []struct {
+		name            string
+		cmd             map[string]interface{}
+		autoRevertAfter types.String
+		client          *fakeDiagnosticClient
+		wantPuts        int
+		wantError       bool
+	}{
+		{"no auto revert", cmd, types.StringNull(), &fakeDiagnosticClient{}, 0, false},
+		{"nothing turned on", map[string]interface{}{"termLB": "disabled"}, types.StringValue("1ms"), &fakeDiagnosticClient{}, 0, false},
+		{"cleared by the device", cmd, types.StringValue("1ms"), &fakeDiagnosticClient{responses: []string{diagnosticOff}}, 0, false},
+		{"reverted", cmd, types.StringValue("1ms"), &fakeDiagnosticClient{responses: []string{diagnosticOn, diagnosticOff}}, 1, false},
+		{"not reverted", cmd, types.StringValue("1ms"), &fakeDiagnosticClient{responses: []string{diagnosticOn}}, 1, true},
+		{"longer than the maximum", cmd, types.StringValue("2h"), &fakeDiagnosticClient{}, 0, true},
+		{"not a duration", cmd, types.StringValue("soon"), &fakeDiagnosticClient{}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			awaitDiagnosticRevert(context.Background(), tt.client, "xr-hub", "resources/ethernets/1/diagnostic", tt.cmd, tt.autoRevertAfter, &diags)
+			if diags.HasError() != tt.wantError {
+				t.Fatalf("awaitDiagnosticRevert() errors = %v, want error %v", diags, tt.wantError)
+			}
+			if len(tt.client.puts) != tt.wantPuts {
+				t.Errorf("awaitDiagnosticRevert() PUTs = %v, want %d", tt.client.puts, tt.wantPuts)
+			}
+		})
+	}
+}
+
+func TestAwaitDiagnosticRevertCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &fakeDiagnosticClient{}
+	cmd := map[string]interface{}{"termLB": "enabled"}
+
+	var diags diag.Diagnostics
+	awaitDiagnosticRevert(ctx, client, "xr-hub", "resources/ethernets/1/diagnostic", cmd, types.StringValue("1h"), &diags)
+	if !diags.HasError() {
+		t.Errorf("awaitDiagnosticRevert() of a cancelled apply has no error")
+	}
+	if len(client.puts) != 0 || client.gets != 0 {
+		t.Errorf("awaitDiagnosticRevert() of a cancelled apply sent %d PUTs and %d GETs", len(client.puts), client.gets)
+	}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import "strings"
+
+// hostControlAttributes are the control attributes of the attributes not
+// named after them, e.g. modulationCtrl.
+var hostControlAttributes = map[string]string{
+	"constellationFrequency": "frequencyCtrl",
+}
+
+// hostControlAttribute returns whether the control attribute of an attribute
+// of a resource content, controlAttribute or else the attribute's own Ctrl or
+// Control attribute, says the host owns it, and whether the device reports
+// one.
+func hostControlAttribute(content map[string]interface{}, attribute string, controlAttribute string) (bool, bool) {
+	if len(attribute) == 0 {
+		return false, false
+	}
+
+	controls := []string{controlAttribute}
+	if len(controlAttribute) == 0 {
+		controls = []string{hostControlAttributes[attribute], attribute + "Ctrl", attribute + "Control"}
+	}
+	for _, c := range controls {
+		if v, ok := content[c].(string); ok && len(c) > 0 {
+			return strings.EqualFold(v, "host"), true
+		}
+	}
+	return false, false
+}
+
+// hostOwned reports whether the host owns an attribute of a resource content,
+// as its control attribute says. The read of a resource keeps the configured
+// value of such an attribute rather than the host's, not to show a diff.
+func hostOwned(content map[string]interface{}, attribute string) bool {
+	owned, _ := hostControlAttribute(content, attribute, "")
+	return owned
+}
+
+// hostControlled reports whether the host controls an attribute of a resource
+// content, for the check, as controlAttribute or else the attribute's own
+// control attribute says, the same as hostOwned. A host value, e.g.
+// hModulation, does not make the attribute the host's.
+func hostControlled(content map[string]interface{}, attribute string, controlAttribute string) bool {
+	owned, _ := hostControlAttribute(content, attribute, controlAttribute)
+	return owned
+}
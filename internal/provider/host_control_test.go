@@ -0,0 +1,58 @@
+package provider
+
+import "testing"
+
+func TestHostControlled(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          map[string]interface{}
+		attribute        string
+		controlAttribute string
+		want             bool
+	}{
+		{"ctrl host", map[string]interface{}{"modulationCtrl": "host"}, "modulation", "", true},
+		{"control host", map[string]interface{}{"portSpeedControl": "Host"}, "portSpeed", "", true},
+		{"ctrl auto", map[string]interface{}{"modulationCtrl": "auto", "hModulation": "16QAM"}, "modulation", "", false},
+		{"mapped control", map[string]interface{}{"frequencyCtrl": "host"}, "constellationFrequency", "", true},
+		{"configured control", map[string]interface{}{"modeOwner": "host", "modulationCtrl": "auto"}, "modulation", "modeOwner", true},
+		// As for hostOwned, a host value alone does not make the attribute
+		// the host's.
+		{"host value only", map[string]interface{}{"hModulation": "16QAM"}, "modulation", "", false},
+		{"mapped host value only", map[string]interface{}{"hFrequency": 193000000.0}, "constellationFrequency", "", false},
+		{"nothing", map[string]interface{}{"modulation": "16QAM"}, "modulation", "", false},
+		{"no attribute", map[string]interface{}{"Ctrl": "host"}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostControlled(tt.content, tt.attribute, tt.controlAttribute); got != tt.want {
+				t.Errorf("hostControlled(%v, %q, %q) = %v, want %v", tt.content, tt.attribute, tt.controlAttribute, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostOwned(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   map[string]interface{}
+		attribute string
+		want      bool
+	}{
+		{"ctrl host", map[string]interface{}{"modulationCtrl": "host"}, "modulation", true},
+		{"control host", map[string]interface{}{"trafficModeControl": "host"}, "trafficMode", true},
+		{"mapped control", map[string]interface{}{"frequencyCtrl": "host"}, "constellationFrequency", true},
+		{"ctrl auto", map[string]interface{}{"modulationCtrl": "auto"}, "modulation", false},
+		// Carriers always report the host values, they do not make the
+		// attribute the host's.
+		{"host value only", map[string]interface{}{"hModulation": "16QAM"}, "modulation", false},
+		{"mapped host value only", map[string]interface{}{"hFrequency": 193000000.0}, "constellationFrequency", false},
+		{"nothing", map[string]interface{}{}, "modulation", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostOwned(tt.content, tt.attribute); got != tt.want {
+				t.Errorf("hostOwned(%v, %q) = %v, want %v", tt.content, tt.attribute, got, tt.want)
+			}
+		})
+	}
+}
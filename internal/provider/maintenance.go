@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maintenanceWindowsModel is the maintenance_windows block of the provider.
+type maintenanceWindowsModel struct {
+	Timezone      types.String             `tfsdk:"timezone"`
+	ResourceTypes []types.String           `tfsdk:"resource_types"`
+	Devices       []types.String           `tfsdk:"devices"`
+	Windows       []maintenanceWindowModel `tfsdk:"window"`
+}
+
+type maintenanceWindowModel struct {
+	Cron     types.String `tfsdk:"cron"`
+	Duration types.String `tfsdk:"duration"`
+	Start    types.String `tfsdk:"start"`
+	End      types.String `tfsdk:"end"`
+}
+
+// maintenanceWindowsBlock is the schema of the maintenance_windows block.
+func maintenanceWindowsBlock() schema.Block {
+	return schema.SingleNestedBlock{
+		Description: "Refuse PUT, POST and DELETE to the XR API outside these windows. XR_MAINTENANCE_OVERRIDE set to the reason of an emergency change lets them through, and is logged.",
+		Attributes: map[string]schema.Attribute{
+			"timezone": schema.StringAttribute{
+				Description: "Time zone of the windows, e.g. Europe/Berlin, default UTC.",
+				Optional:    true,
+			},
+			"resource_types": schema.ListAttribute{
+				Description: "Only restrict these resource types, e.g. xrcm_carrier, default all. Only the resources of a device collection, e.g. carriers, and their diagnostic resources are known by the href they write; others, e.g. xrcm_resource, are rejected here and restricted only when resource_types is not set.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"devices": schema.ListAttribute{
+				Description: "Only restrict the devices whose whole name matches one of these regular expressions, as allowed_devices, e.g. core-.*, default all.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"window": schema.ListNestedBlock{
+				Description: "A window, either recurring with cron and duration or absolute with start and end.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"cron": schema.StringAttribute{
+							Description: "Start of a recurring window, as minute hour day-of-month month day-of-week, e.g. \"0 2 * * SAT\".",
+							Optional:    true,
+						},
+						"duration": schema.StringAttribute{
+							Description: "Length of a recurring window, e.g. 4h.",
+							Optional:    true,
+						},
+						"start": schema.StringAttribute{
+							Description: "Start of an absolute window, RFC 3339 or 2006-01-02T15:04 in the time zone.",
+							Optional:    true,
+						},
+						"end": schema.StringAttribute{
+							Description: "End of an absolute window, RFC 3339 or 2006-01-02T15:04 in the time zone.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getMaintenanceWindows returns the maintenance windows of the provider
+// configuration, or nil if it has none. deviceTypes are the resource types
+// the windows can restrict by collection, see deviceResourceTypes.
+func getMaintenanceWindows(ctx context.Context, config *maintenanceWindowsModel, deviceTypes map[string]string, diags *diag.Diagnostics) *xrcm_pf.MaintenanceWindows {
+	if config == nil {
+		return nil
+	}
+
+	blockPath := path.Root("maintenance_windows")
+	mw := &xrcm_pf.MaintenanceWindows{Location: time.UTC}
+	if !config.Timezone.IsNull() {
+		loc, err := time.LoadLocation(config.Timezone.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				blockPath.AtName("timezone"),
+				"Invalid XR API Maintenance Windows",
+				"The provider cannot create the XR API client as the time zone is not known: "+err.Error(),
+			)
+			return nil
+		}
+		mw.Location = loc
+	}
+	var resourceTypes []string
+	for _, t := range deviceTypes {
+		resourceTypes = append(resourceTypes, t)
+	}
+	sort.Strings(resourceTypes)
+	for i, v := range config.ResourceTypes {
+		if !containsString(resourceTypes, v.ValueString()) {
+			diags.AddAttributeError(
+				blockPath.AtName("resource_types").AtListIndex(i),
+				"Invalid XR API Maintenance Windows",
+				"The provider cannot create the XR API client as "+v.ValueString()+" is not a resource type the maintenance windows can restrict, expected one of "+strings.Join(resourceTypes, ", ")+".",
+			)
+			continue
+		}
+		mw.ResourceTypes = append(mw.ResourceTypes, v.ValueString())
+	}
+	mw.Devices = getDevicePatterns(config.Devices, blockPath.AtName("devices"), diags)
+
+	for i, w := range config.Windows {
+		windowPath := blockPath.AtName("window").AtListIndex(i)
+		var window xrcm_pf.MaintenanceWindow
+		var err error
+		switch {
+		case !w.Cron.IsNull() && w.Start.IsNull() && w.End.IsNull():
+			window.Schedule, err = xrcm_pf.ParseSchedule(w.Cron.ValueString())
+			if err == nil {
+				window.Duration, err = time.ParseDuration(w.Duration.ValueString())
+			}
+			if err == nil && window.Duration <= 0 {
+				err = fmt.Errorf("duration %s must be positive", w.Duration.ValueString())
+			}
+		case w.Cron.IsNull() && w.Duration.IsNull() && !w.Start.IsNull() && !w.End.IsNull():
+			window.Start, err = parseWindowTime(w.Start.ValueString(), mw.Location)
+			if err == nil {
+				window.End, err = parseWindowTime(w.End.ValueString(), mw.Location)
+			}
+			if err == nil && !window.End.After(window.Start) {
+				err = fmt.Errorf("end %s must be after start %s", w.End.ValueString(), w.Start.ValueString())
+			}
+		default:
+			diags.AddAttributeError(
+				windowPath,
+				"Invalid XR API Maintenance Windows",
+				"The provider cannot create the XR API client as a window must set either cron and duration or start and end.",
+			)
+			continue
+		}
+		if err != nil {
+			diags.AddAttributeError(
+				windowPath,
+				"Invalid XR API Maintenance Windows",
+				"The provider cannot create the XR API client as a window is not valid: "+err.Error(),
+			)
+			continue
+		}
+		mw.Windows = append(mw.Windows, window)
+	}
+
+	if reason, ok := os.LookupEnv("XR_MAINTENANCE_OVERRIDE"); ok && reason != "" {
+		mw.Override = true
+		mw.OverrideReason = reason
+		tflog.Warn(ctx, "provider: XRCM - maintenance windows overridden by XR_MAINTENANCE_OVERRIDE", map[string]interface{}{"reason": reason})
+	}
+	return mw
+}
+
+// parseWindowTime parses the start or end of an absolute window.
+func parseWindowTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, loc); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04", value, loc)
+}
+
+// deviceResourceCollections are the resources writing the collections of the
+// device hrefs, and their diagnostic resources, see xrcm_pf.ResourceType.
+var deviceResourceCollections = map[string]func() resource.Resource{
+	"cfg":                  NewCfgResource,
+	"lineptps":             NewLinePTPResource,
+	"carriers":             NewCarrierResource,
+	"carriers/diagnostic":  NewCarrierDiagResource,
+	"dscgs":                NewDSCGResource,
+	"dscs":                 NewDSCResource,
+	"dscs/diagnostic":      NewDSCDiagResource,
+	"ethernets":            NewEthernetResource,
+	"ethernets/diagnostic": NewEthernetDiagResource,
+	"acs":                  NewACResource,
+	"acs/diagnostic":       NewACDiagResource,
+	"lcs":                  NewLCResource,
+	"otus":                 NewOTUResource,
+	"otus/diagnostic":      NewOTUDiagResource,
+	"odus":                 NewODUResource,
+	"lldp-cfg":             NewEthernetLLDPResource,
+}
+
+// resourceTypeName returns the type name a resource registers.
+func resourceTypeName(ctx context.Context, providerTypeName string, newResource func() resource.Resource) string {
+	resp := resource.MetadataResponse{}
+	newResource().Metadata(ctx, resource.MetadataRequest{ProviderTypeName: providerTypeName}, &resp)
+	return resp.TypeName
+}
+
+// deviceResourceTypes returns the type names of deviceResourceCollections by
+// collection, for xrcm_pf.Client.ResourceTypes, leaving out the resources not
+// among the registered resourceTypes.
+func deviceResourceTypes(ctx context.Context, providerTypeName string, resourceTypes []string) map[string]string {
+	types := make(map[string]string, len(deviceResourceCollections))
+	for collection, newResource := range deviceResourceCollections {
+		if t := resourceTypeName(ctx, providerTypeName, newResource); containsString(resourceTypes, t) {
+			types[collection] = t
+		}
+	}
+	return types
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
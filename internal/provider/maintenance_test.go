@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDeviceResourceTypes(t *testing.T) {
+	ctx := context.Background()
+	var registered []string
+	for _, newResource := range New().Resources(ctx) {
+		registered = append(registered, resourceTypeName(ctx, providerTypeName, newResource))
+	}
+
+	types := deviceResourceTypes(ctx, providerTypeName, registered)
+	for collection, want := range map[string]string{
+		"lineptps":            "xrcm_LinePTP",
+		"carriers":            "xrcm_carrier",
+		"carriers/diagnostic": "xrcm_carrier_diag",
+		"ethernets":           "xrcm_ethernet",
+	} {
+		if got := types[collection]; got != want {
+			t.Errorf("deviceResourceTypes()[%q] = %q, want %q", collection, got, want)
+		}
+	}
+	for collection, typeName := range types {
+		if !containsString(registered, typeName) {
+			t.Errorf("deviceResourceTypes()[%q] = %q is not a registered resource type", collection, typeName)
+		}
+	}
+}
+
+func TestGetMaintenanceWindows(t *testing.T) {
+	deviceTypes := map[string]string{"carriers": "xrcm_carrier", "carriers/diagnostic": "xrcm_carrier_diag"}
+
+	tests := []struct {
+		name          string
+		resourceTypes []string
+		devices       []string
+		wantErr       bool
+	}{
+		{"device collection", []string{"xrcm_carrier", "xrcm_carrier_diag"}, nil, false},
+		{"no collection", []string{"xrcm_resource"}, nil, true},
+		{"devices", nil, []string{"core-.*"}, false},
+		{"devices not a regular expression", nil, []string{"core-("}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &maintenanceWindowsModel{Timezone: types.StringNull()}
+			for _, v := range tt.resourceTypes {
+				config.ResourceTypes = append(config.ResourceTypes, types.StringValue(v))
+			}
+			for _, v := range tt.devices {
+				config.Devices = append(config.Devices, types.StringValue(v))
+			}
+			var diags diag.Diagnostics
+			mw := getMaintenanceWindows(context.Background(), config, deviceTypes, &diags)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("getMaintenanceWindows() errors = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if !tt.wantErr && len(mw.ResourceTypes) != len(tt.resourceTypes) {
+				t.Errorf("getMaintenanceWindows() resource types = %v, want %v", mw.ResourceTypes, tt.resourceTypes)
+			}
+			if !tt.wantErr && len(tt.devices) > 0 && (!mw.Devices[0].MatchString("core-1") || mw.Devices[0].MatchString("edge-core-1")) {
+				t.Errorf("getMaintenanceWindows() devices = %v, want whole names matching %v", mw.Devices, tt.devices)
+			}
+		})
+	}
+}
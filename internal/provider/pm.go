@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// PMValueData is a performance monitoring value: the current value, and the
+// min and max of the current period when the module reports them.
+type PMValueData struct {
+	Current types.Float64 `tfsdk:"current"`
+	Min     types.Float64 `tfsdk:"min"`
+	Max     types.Float64 `tfsdk:"max"`
+}
+
+// pm is the current PM of a resource.
+type pm struct {
+	resourceId string
+	href       string
+	metrics    map[string]PMValueData
+}
+
+// pmMetricsAttribute is the schema of the metrics of a PM.
+func pmMetricsAttribute(description string) schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		Description: description,
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"current": schema.Float64Attribute{
+					Description: "Current value",
+					Computed:    true,
+				},
+				"min": schema.Float64Attribute{
+					Description: "Minimum of the current period, if reported",
+					Computed:    true,
+				},
+				"max": schema.Float64Attribute{
+					Description: "Maximum of the current period, if reported",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// readPMs returns the current PM of the resources of type rsType whose href is
+// prefix followed by their id, e.g. the carriers of a line PTP, only of ids
+// when set.
+func readPMs(ctx context.Context, client *xrcm_pf.Client, deviceName string, rsType string, prefix string, ids []types.String) ([]pm, string, error) {
+	var hrefs []string
+	deviceId := ""
+	if len(ids) > 0 {
+		for _, id := range ids {
+			hrefs = append(hrefs, prefix+id.ValueString())
+		}
+	} else {
+		data, dId, err := GetResource(ctx, client, deviceName, "resource-links")
+		if err != nil {
+			return nil, "", err
+		}
+		deviceId = dId
+		resources, _ := data["resources"].([]interface{})
+		for _, v := range resources {
+			rec, _ := v.(map[string]interface{})
+			rsTypes, _ := rec["resourceTypes"].([]interface{})
+			href, _ := rec["href"].(string)
+			if len(rsTypes) == 0 || rsTypes[0] != rsType || !strings.HasPrefix(href, prefix) {
+				continue
+			}
+			hrefs = append(hrefs, href)
+		}
+	}
+
+	var pms []pm
+	for _, href := range hrefs {
+		data, dId, err := GetResource(ctx, client, deviceName, "resources"+href+"/pm/current")
+		if err != nil {
+			return nil, deviceId, err
+		}
+		deviceId = dId
+		resultData, _ := data["data"].(map[string]interface{})
+		content, _ := resultData["content"].(map[string]interface{})
+		pms = append(pms, pm{
+			resourceId: href[len(prefix):],
+			href:       href,
+			metrics:    getPMMetrics(content),
+		})
+	}
+	tflog.Debug(ctx, "readPMs: ", map[string]interface{}{"Device": deviceName, "hrefs": hrefs})
+	return pms, deviceId, nil
+}
+
+// getPMMetrics returns the numeric values of a PM content. A value is either a
+// number, current only, or an object with current, min and max.
+func getPMMetrics(content map[string]interface{}) map[string]PMValueData {
+	metrics := make(map[string]PMValueData)
+	for k, v := range content {
+		if current, ok := pmNumber(v); ok {
+			metrics[k] = PMValueData{Current: current, Min: types.Float64Null(), Max: types.Float64Null()}
+			continue
+		}
+		rec, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value := PMValueData{Current: types.Float64Null(), Min: types.Float64Null(), Max: types.Float64Null()}
+		if current, ok := pmNumber(rec["current"]); ok {
+			value.Current = current
+		} else if current, ok := pmNumber(rec["value"]); ok {
+			value.Current = current
+		}
+		if min, ok := pmNumber(rec["min"]); ok {
+			value.Min = min
+		}
+		if max, ok := pmNumber(rec["max"]); ok {
+			value.Max = max
+		}
+		if value.Current.IsNull() && value.Min.IsNull() && value.Max.IsNull() {
+			continue
+		}
+		metrics[k] = value
+	}
+	return metrics
+}
+
+// pmNumber returns a PM value reported as a number or as a numeric string.
+func pmNumber(v interface{}) (types.Float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return types.Float64Value(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return types.Float64Null(), false
+		}
+		return types.Float64Value(f), true
+	}
+	return types.Float64Null(), false
+}
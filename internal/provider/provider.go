@@ -3,10 +3,14 @@ package provider
 import (
 	"context"
 	"os"
+	"regexp"
+	"strconv"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
+	"github.com/google/martian/v3/log"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -15,9 +19,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// defaultMaxConcurrency is the number of modules data sources query in parallel
+// unless max_concurrency says otherwise.
+const defaultMaxConcurrency = 4
+
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
 var _ provider.Provider = &XRProvider{}
 
+// providerTypeName prefixes the type names of the resources and data sources.
+const providerTypeName = "xrcm"
+
 // New is a helper function to simplify provider server and testing implementation.
 func New() provider.Provider {
 	return &XRProvider{}
@@ -31,13 +42,21 @@ type XRProvider struct {
 
 // providerData can be used to store data from the Terraform configuration.
 type XRProviderModel struct {
-	Username types.String `tfsdk:"username"`
-	Host     types.String `tfsdk:"host"`
-	Password types.String `tfsdk:"password"`
+	Username           types.String   `tfsdk:"username"`
+	Host               types.String   `tfsdk:"host"`
+	Password           types.String   `tfsdk:"password"`
+	MaxConcurrency     types.Int64    `tfsdk:"max_concurrency"`
+	ReadOnly           types.Bool     `tfsdk:"read_only"`
+	AllowTrafficImpact types.Bool     `tfsdk:"allow_traffic_impact"`
+	AllowedDevices     []types.String `tfsdk:"allowed_devices"`
+	DeniedDevices      []types.String `tfsdk:"denied_devices"`
+
+	MaintenanceWindows *maintenanceWindowsModel `tfsdk:"maintenance_windows"`
+	DeviceResolvers    []deviceResolverModel    `tfsdk:"device_resolvers"`
 }
 
 func (p *XRProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
-	resp.TypeName = "xrcm"
+	resp.TypeName = providerTypeName
 	resp.Version = p.version
 }
 
@@ -59,6 +78,32 @@ func (p *XRProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *p
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"max_concurrency": schema.Int64Attribute{
+				Description: "Maximum number of modules data sources query in parallel, default 4. May also be provided via XR_MAX_CONCURRENCY environment variable.",
+				Optional:    true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Refuse every PUT, POST and DELETE to the XR API, default false. May also be provided via XR_READ_ONLY environment variable.",
+				Optional:    true,
+			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow traffic affecting changes on modules with active LCs for every resource, default false. May also be provided via XR_ALLOW_TRAFFIC_IMPACT environment variable.",
+				Optional:    true,
+			},
+			"allowed_devices": schema.ListAttribute{
+				Description: "Regular expressions matching the whole name of the only devices the provider reads and writes, default all.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"denied_devices": schema.ListAttribute{
+				Description: "Regular expressions matching the whole name of devices the provider never reads nor writes, even if allowed.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"device_resolvers": deviceResolversAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"maintenance_windows": maintenanceWindowsBlock(),
 		},
 	}
 }
@@ -126,6 +171,57 @@ func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureReques
 		password = config.Password.ValueString()
 	}
 
+	maxConcurrency, err := strconv.Atoi(os.Getenv("XR_MAX_CONCURRENCY"))
+	if err != nil {
+		maxConcurrency = defaultMaxConcurrency
+	}
+
+	if !config.MaxConcurrency.IsNull() {
+		maxConcurrency = int(config.MaxConcurrency.ValueInt64())
+	}
+
+	readOnly := false
+	if v, ok := os.LookupEnv("XR_READ_ONLY"); ok && v != "" {
+		readOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("read_only"),
+				"Invalid XR API Read Only",
+				"The provider cannot create the XR API client as XR_READ_ONLY is not a boolean: "+err.Error(),
+			)
+		}
+	}
+
+	if !config.ReadOnly.IsNull() {
+		readOnly = config.ReadOnly.ValueBool()
+	}
+
+	allowTrafficImpact := false
+	if v, ok := os.LookupEnv("XR_ALLOW_TRAFFIC_IMPACT"); ok && v != "" {
+		allowTrafficImpact, err = strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("allow_traffic_impact"),
+				"Invalid XR API Allow Traffic Impact",
+				"The provider cannot create the XR API client as XR_ALLOW_TRAFFIC_IMPACT is not a boolean: "+err.Error(),
+			)
+		}
+	}
+
+	if !config.AllowTrafficImpact.IsNull() {
+		allowTrafficImpact = config.AllowTrafficImpact.ValueBool()
+	}
+
+	var resourceTypes []string
+	for _, newResource := range p.Resources(ctx) {
+		resourceTypes = append(resourceTypes, resourceTypeName(ctx, providerTypeName, newResource))
+	}
+	deviceTypes := deviceResourceTypes(ctx, providerTypeName, resourceTypes)
+	maintenance := getMaintenanceWindows(ctx, config.MaintenanceWindows, deviceTypes, &resp.Diagnostics)
+
+	allowedDevices := getDevicePatterns(config.AllowedDevices, path.Root("allowed_devices"), &resp.Diagnostics)
+	deniedDevices := getDevicePatterns(config.DeniedDevices, path.Root("denied_devices"), &resp.Diagnostics)
+
 	if host == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
@@ -156,6 +252,15 @@ func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureReques
 		)
 	}
 
+	if maxConcurrency < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrency"),
+			"Invalid XR API Max Concurrency",
+			"The provider cannot create the XR API client as max_concurrency must be at least 1. "+
+				"Set the max_concurrency value in the configuration or use the XR_MAX_CONCURRENCY environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -167,6 +272,9 @@ func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureReques
 
 	tflog.Debug(ctx, "Creating XR client")
 
+	// The client logs through martian, the provider logs all of it.
+	log.SetLevel(log.Debug)
+
 	// Create a new XRCM client and set it to the provider client
 	client, err := xrcm_pf.NewClient(&host, &username, &password)
 
@@ -185,6 +293,39 @@ func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureReques
 
 	tflog.Debug(ctx, "provider: XRCM - successful connection request")
 	client.Devicemap = make(map[string]string)
+	client.MaxConcurrency = maxConcurrency
+	client.ReadOnly = readOnly
+	if readOnly {
+		tflog.Info(ctx, "provider: XRCM - read only mode, PUT, POST and DELETE are refused")
+	}
+	client.AllowTrafficImpact = allowTrafficImpact
+	if allowTrafficImpact {
+		tflog.Info(ctx, "provider: XRCM - traffic affecting changes are allowed on modules with active LCs")
+	}
+	client.Maintenance = maintenance
+	client.ResourceTypes = deviceTypes
+	client.AllowedDevices = allowedDevices
+	client.DeniedDevices = deniedDevices
+	client.Resolvers = getDeviceResolvers(ctx, client, config.DeviceResolvers, &resp.Diagnostics)
+}
+
+// getDevicePatterns compiles the device name patterns of the attribute at
+// attributePath, each matching the whole name.
+func getDevicePatterns(patterns []types.String, attributePath path.Path, diags *diag.Diagnostics) []*regexp.Regexp {
+	var res []*regexp.Regexp
+	for i, p := range patterns {
+		re, err := regexp.Compile("^(?:" + p.ValueString() + ")$")
+		if err != nil {
+			diags.AddAttributeError(
+				attributePath.AtListIndex(i),
+				"Invalid XR API Device Pattern",
+				"The provider cannot create the XR API client as "+attributePath.String()+" is not a regular expression: "+err.Error(),
+			)
+			continue
+		}
+		res = append(res, re)
+	}
+	return res
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -204,6 +345,11 @@ func (p *XRProvider) DataSources(_ context.Context) []func() datasource.DataSour
 		NewLCsDataSource,
 		NewLineNeighborDataSource,
 		NewDeviceIdsDataSource,
+		NewDeviceResourcesDataSource,
+		NewCarrierPMDataSource,
+		NewEthernetPMDataSource,
+		NewOTUPMDataSource,
+		NewAlarmsDataSource,
 	}
 }
 
@@ -223,6 +369,8 @@ func (p *XRProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewLCResource,
 		NewODUResource,
 		NewOTUResource,
+		NewPRBSTestResource,
 		NewLinePTPResource,
+		NewGenericResource,
 	}
 }
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Formats of the verification reports.
+const (
+	reportFormatJSON  = "json"
+	reportFormatJUnit = "junit"
+)
+
+// checkReport is the verification report of a check data source.
+type checkReport struct {
+	Name      string             `json:"name"`
+	Generated string             `json:"generated"`
+	Passed    bool               `json:"passed"`
+	Results   []checkReportEntry `json:"results"`
+}
+
+// checkReportEntry is the result of an attribute of a resource, or of a check.
+type checkReportEntry struct {
+	Device      string `json:"device,omitempty"`
+	Href        string `json:"href,omitempty"`
+	Attribute   string `json:"attribute,omitempty"`
+	Operator    string `json:"operator,omitempty"`
+	IntentValue string `json:"intentvalue,omitempty"`
+	DeviceValue string `json:"devicevalue,omitempty"`
+	Description string `json:"description,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Match       bool   `json:"match"`
+}
+
+// reportAttributes are the schema attributes of the report of a check data
+// source.
+func reportAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["report_path"] = schema.StringAttribute{
+		Description: "Write the verification report to this file",
+		Optional:    true,
+	}
+	attributes["report_format"] = schema.StringAttribute{
+		Description: "json or junit, default junit for a report_path ending in .xml and json otherwise",
+		Optional:    true,
+	}
+	return attributes
+}
+
+// newCheckReport returns an empty report of the data source name.
+func newCheckReport(name string) *checkReport {
+	return &checkReport{
+		Name:      name,
+		Generated: time.Now().UTC().Format(time.RFC3339),
+		Passed:    true,
+		Results:   []checkReportEntry{},
+	}
+}
+
+func (r *checkReport) add(entry checkReportEntry) {
+	r.Results = append(r.Results, entry)
+	// Warnings do not fail the report, as they do not fail the checks.
+	if !entry.Match && entry.Severity != checkSeverityWarning {
+		r.Passed = false
+	}
+}
+
+// write writes the report to path in format, JSON or JUnit XML.
+func (r *checkReport) write(path types.String, format types.String) error {
+	if path.IsNull() || len(path.ValueString()) == 0 {
+		return nil
+	}
+	f := strings.ToLower(format.ValueString())
+	if len(f) == 0 {
+		f = reportFormatJSON
+		if strings.EqualFold(filepath.Ext(path.ValueString()), ".xml") {
+			f = reportFormatJUnit
+		}
+	}
+
+	var b []byte
+	var err error
+	switch f {
+	case reportFormatJSON:
+		b, err = json.MarshalIndent(r, "", "  ")
+	case reportFormatJUnit:
+		b, err = r.junit()
+	default:
+		return errors.New("invalid report_format " + format.ValueString() + ", expected json or junit")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path.ValueString()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path.ValueString(), b, 0644)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junit returns the report as JUnit XML, a test suite by device and a test
+// case by attribute or check.
+func (r *checkReport) junit() ([]byte, error) {
+	suites := junitTestSuites{Name: r.Name}
+	index := make(map[string]int)
+	for _, e := range r.Results {
+		suiteName := e.Device
+		if len(suiteName) == 0 {
+			suiteName = r.Name
+		}
+		i, ok := index[suiteName]
+		if !ok {
+			i = len(suites.Suites)
+			index[suiteName] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: suiteName, Timestamp: r.Generated})
+		}
+
+		name := strings.TrimSpace(e.Href + " " + e.Attribute)
+		if len(e.Description) > 0 {
+			name = strings.TrimSpace(name + " " + e.Description)
+		}
+		tc := junitTestCase{Name: name, ClassName: r.Name + "." + suiteName}
+		detail := "intent " + e.Operator + " " + e.IntentValue + ", device " + e.DeviceValue
+		if len(e.Attribute) == 0 {
+			detail = e.Severity
+		}
+		if !e.Match {
+			if e.Severity == checkSeverityWarning {
+				tc.SystemOut = "warning: " + name + " failed"
+			} else {
+				tc.Failure = &junitFailure{Message: name + " does not match", Text: detail}
+				suites.Suites[i].Failures++
+				suites.Failures++
+			}
+		}
+		suites.Suites[i].Cases = append(suites.Suites[i].Cases, tc)
+		suites.Suites[i].Tests++
+		suites.Tests++
+	}
+
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Types of the device resolvers of the provider.
+const (
+	resolverStatic        = "static"
+	resolverTFState       = "tfstate"
+	resolverNamingService = "naming_service"
+	resolverDiscovery     = "discovery"
+)
+
+// defaultResolverNegativeTTL is how long resolvers remember the names they do
+// not know, unless negative_ttl is set.
+const defaultResolverNegativeTTL = time.Minute
+
+// deviceResolverModel is an element of the device_resolvers of the provider.
+type deviceResolverModel struct {
+	Type          types.String `tfsdk:"type"`
+	Path          types.String `tfsdk:"path"`
+	State         types.String `tfsdk:"state"`
+	Address       types.String `tfsdk:"address"`
+	Attribute     types.String `tfsdk:"attribute"`
+	NameAttribute types.String `tfsdk:"name_attribute"`
+	IdAttribute   types.String `tfsdk:"id_attribute"`
+	Endpoint      types.String `tfsdk:"endpoint"`
+	TTL           types.String `tfsdk:"ttl"`
+	NegativeTTL   types.String `tfsdk:"negative_ttl"`
+}
+
+// deviceResolversAttribute is the schema of the device_resolvers of the
+// provider.
+func deviceResolversAttribute() schema.Attribute {
+	return schema.ListNestedAttribute{
+		Description: "Resolve device names to ids with these resolvers, the first knowing a device wins. Default the XR naming service if XRCM_NAMING_SERVICE is set, else the CM discovery.",
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					Description: "static, tfstate, naming_service or discovery",
+					Required:    true,
+				},
+				"path": schema.StringAttribute{
+					Description: "static: file of the devices, a JSON object of ids by name or a JSON list of objects with name and id",
+					Optional:    true,
+				},
+				"state": schema.StringAttribute{
+					Description: "tfstate: Terraform state, a file path or the URL of its backend, default terraform.tfstate of the working directory",
+					Optional:    true,
+				},
+				"address": schema.StringAttribute{
+					Description: "tfstate: address of the resource, data source or output holding the devices, default every xrcm_detaildevices",
+					Optional:    true,
+				},
+				"attribute": schema.StringAttribute{
+					Description: "tfstate: attribute of address holding the devices, default devices",
+					Optional:    true,
+				},
+				"name_attribute": schema.StringAttribute{
+					Description: "tfstate: attribute of a device holding its name, default name",
+					Optional:    true,
+				},
+				"id_attribute": schema.StringAttribute{
+					Description: "tfstate: attribute of a device holding its id, default deviceid",
+					Optional:    true,
+				},
+				"endpoint": schema.StringAttribute{
+					Description: "naming_service: endpoint of the XR naming service, default XRCM_NAMING_SERVICE",
+					Optional:    true,
+				},
+				"ttl": schema.StringAttribute{
+					Description: "How long the ids resolved are kept, e.g. 10m, default as long as the provider runs",
+					Optional:    true,
+				},
+				"negative_ttl": schema.StringAttribute{
+					Description: "How long the names not resolved are remembered, e.g. 30s, 0s not to, default 1m",
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// getDeviceResolvers returns the device resolvers of the provider, each
+// caching its ids and unknown names.
+func getDeviceResolvers(ctx context.Context, client *xrcm_pf.Client, resolvers []deviceResolverModel, diags *diag.Diagnostics) []xrcm_pf.DeviceResolver {
+	var res []xrcm_pf.DeviceResolver
+	for i, r := range resolvers {
+		p := path.Root("device_resolvers").AtListIndex(i)
+
+		var resolver xrcm_pf.DeviceResolver
+		switch r.Type.ValueString() {
+		case resolverStatic:
+			if len(r.Path.ValueString()) == 0 {
+				diags.AddAttributeError(p.AtName("path"), "Missing XR API Device Resolver Path", "The provider cannot create the static device resolver without a path.")
+				continue
+			}
+			resolver = &xrcm_pf.StaticResolver{Path: r.Path.ValueString()}
+		case resolverTFState:
+			t := &tfstateResolver{
+				State:         "terraform.tfstate",
+				Address:       r.Address.ValueString(),
+				Attribute:     "devices",
+				NameAttribute: "name",
+				IdAttribute:   "deviceid",
+			}
+			if !r.State.IsNull() {
+				t.State = r.State.ValueString()
+			}
+			if !r.Attribute.IsNull() {
+				t.Attribute = r.Attribute.ValueString()
+			}
+			if !r.NameAttribute.IsNull() {
+				t.NameAttribute = r.NameAttribute.ValueString()
+			}
+			if !r.IdAttribute.IsNull() {
+				t.IdAttribute = r.IdAttribute.ValueString()
+			}
+			resolver = t
+		case resolverNamingService:
+			endpoint := os.Getenv("XRCM_NAMING_SERVICE")
+			if !r.Endpoint.IsNull() {
+				endpoint = r.Endpoint.ValueString()
+			}
+			if len(endpoint) == 0 {
+				diags.AddAttributeError(p.AtName("endpoint"), "Missing XR API Device Resolver Endpoint", "The provider cannot create the naming_service device resolver without an endpoint. "+
+					"Set the endpoint value in the configuration or use the XRCM_NAMING_SERVICE environment variable.")
+				continue
+			}
+			resolver = &xrcm_pf.NamingServiceResolver{Endpoint: endpoint}
+		case resolverDiscovery:
+			resolver = &xrcm_pf.DiscoveryResolver{Client: client}
+		default:
+			diags.AddAttributeError(p.AtName("type"), "Invalid XR API Device Resolver Type",
+				"The provider cannot create the device resolver "+r.Type.ValueString()+", expected static, tfstate, naming_service or discovery.")
+			continue
+		}
+
+		ttl := getResolverTTL(r.TTL, 0, p.AtName("ttl"), diags)
+		negativeTTL := getResolverTTL(r.NegativeTTL, defaultResolverNegativeTTL, p.AtName("negative_ttl"), diags)
+		tflog.Debug(ctx, "provider: XRCM - device resolver", map[string]interface{}{"type": resolver.Name(), "ttl": ttl.String(), "negative_ttl": negativeTTL.String()})
+		res = append(res, xrcm_pf.NewCachedResolver(resolver, ttl, negativeTTL))
+	}
+	return res
+}
+
+// getResolverTTL returns the duration of a ttl attribute, def when not set.
+func getResolverTTL(v types.String, def time.Duration, p path.Path, diags *diag.Diagnostics) time.Duration {
+	if v.IsNull() || len(v.ValueString()) == 0 {
+		return def
+	}
+	d, err := time.ParseDuration(v.ValueString())
+	if err != nil || d < 0 {
+		msg := "negative"
+		if err != nil {
+			msg = err.Error()
+		}
+		diags.AddAttributeError(p, "Invalid XR API Device Resolver TTL", "The provider cannot create the device resolver as "+v.ValueString()+" is not a duration: "+msg)
+		return def
+	}
+	return d
+}
+
+// tfstateResolver resolves the devices of a Terraform state, see
+// xrcm_devices_ids.
+type tfstateResolver struct {
+	State         string
+	Address       string
+	Attribute     string
+	NameAttribute string
+	IdAttribute   string
+}
+
+func (r *tfstateResolver) Name() string {
+	return resolverTFState
+}
+
+func (r *tfstateResolver) Resolve(devicename string) (string, error) {
+	return xrcm_pf.ResolveListed(r, devicename)
+}
+
+// List reads the devices of the state, read once by GetTFState.
+func (r *tfstateResolver) List() (map[string]string, error) {
+	ctx := context.Background()
+	state, err := GetTFState(ctx, r.State)
+	if err != nil {
+		return nil, err
+	}
+	deviceIds, err := tfstateDevices(ctx, state, r.Address, r.Attribute, r.NameAttribute, r.IdAttribute)
+	if err != nil {
+		return nil, err
+	}
+	devices := make(map[string]string, len(deviceIds))
+	for _, d := range deviceIds {
+		devices[d.N.ValueString()] = d.Id.ValueString()
+	}
+	return devices, nil
+}
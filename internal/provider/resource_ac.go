@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &ACResource{}
 	_ resource.ResourceWithConfigure   = &ACResource{}
 	_ resource.ResourceWithImportState = &ACResource{}
+	_ resource.ResourceWithModifyPlan  = &ACResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -49,6 +50,8 @@ type ACResourceData struct {
 	AcCtrl      types.Int64  `tfsdk:"acctrl"`
 	MaxPktLen  types.Int64  `tfsdk:"maxpktlen"`
 	ConfigState    types.String `tfsdk:"configstate"`
+	AdoptExisting  types.Bool   `tfsdk:"adopt_existing"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -120,6 +123,14 @@ func (r *ACResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Description: "configstate",
 				Computed:    true,
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "Take over an AC that already exists on the module instead of failing the create.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values or adopt_existing is set.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -133,6 +144,11 @@ func (r *ACResource) Configure(_ context.Context, req resource.ConfigureRequest,
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *ACResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 func (r ACResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data ACResourceData
 
@@ -146,6 +162,7 @@ func (r ACResource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.create(&data, ctx, &resp.Diagnostics)
 
 	if data.Id.IsNull() {
@@ -188,6 +205,7 @@ func (r ACResource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -225,6 +243,67 @@ func (r *ACResource) create(plan *ACResourceData, ctx context.Context, diags *di
 		return
 	}
 	tflog.Debug(ctx, "ACResource: create ## ", map[string]interface{}{"Acid": plan.AcId.ValueString(), "EthernetId": plan.EthernetId.ValueString()})
+
+	if plan.AdoptExisting.ValueBool() && r.adopt(plan, ctx, diags) {
+		return
+	}
+
+	uri, cmd := r.createRequest(plan)
+
+	rb, err := json.Marshal(cmd)
+
+	if err != nil {
+		diags.AddError(
+			"ACResource: create ##: Error Create AC",
+			"Create: Could not Marshal AC, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "ACResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "cmd": string(rb)})
+
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", uri, rb)
+
+	if err != nil {
+		diags.AddError(
+			"ACResource: create ##: Error creating AC",
+			"Create: Could not create AC, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "ACResource: create ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+
+	href := createdHref(body, uri, plan.AcId.ValueString())
+	defer rollbackOnError(ctx, r.client, plan.N.ValueString(), diags, &plan.Id, func() string { return href })
+
+	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+	if err != nil {
+		diags.AddError(
+			"ACResource: create ##: Error Create AC",
+			"Create: Could not AC SetResourceId, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	rep, ok := content["rep"].(map[string]interface{})
+	if !ok {
+		diags.AddError(
+			"ACResource: create ##: Error Create AC",
+			"Create: Could not create AC, no rep data in response: "+string(body),
+		)
+		return
+	}
+	aid := rep["aid"]
+	if aid != nil && len(aid.(string)) > 0 {
+		plan.Aid = types.StringValue(aid.(string))
+	} else {
+		plan.Aid = types.StringValue("")
+	}
+	plan.DeviceId = types.StringValue(deviceId)
+
+	tflog.Debug(ctx, "ACResource: create ##", map[string]interface{}{"plan": plan})
+}
+
+// createRequest returns the device command uri and body create POSTs for the plan.
+func (r *ACResource) createRequest(plan *ACResourceData) (string, map[string]interface{}) {
 	var rep = make(map[string]interface{})
 
 	if !(plan.Capacity.IsNull()) {
@@ -268,50 +347,87 @@ func (r *ACResource) create(plan *ACResourceData, ctx context.Context, diags *di
 	var p = make(map[string]int)
 	p["bm"] = 1
 	cmd["p"] = p
-	if len(cmd) == 0. {
-		return
+
+	return "resource-links/ethernets/" + plan.EthernetId.ValueString() + "/acs", cmd
+}
+
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *ACResource) updateRequest(plan *ACResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.Capacity.IsNull()) {
+		cmd["capacity"] = plan.Capacity.ValueInt64()
 	}
 
-	rb, err := json.Marshal(cmd)
+	if !(plan.Imc.IsNull()) {
+		cmd["imc"] = plan.Imc.ValueString()
+	}
 
-	if err != nil {
-		diags.AddError(
-			"ACResource: create ##: Error Create AC",
-			"Create: Could not Marshal AC, unexpected error: "+err.Error(),
-		)
-		return
+	if !(plan.ImcOuterVID.IsNull()) {
+		cmd["imcOuterVID"] = plan.ImcOuterVID.ValueString()
 	}
-	tflog.Debug(ctx, "ACResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resource-links/ethernets/" + plan.EthernetId.ValueString() + "/acs", "cmd": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", "resource-links/ethernets/"+plan.EthernetId.ValueString()+"/acs", rb)
+	if !(plan.Emc.IsNull()) {
+		cmd["emc"] = plan.Emc.ValueString()
+	}
 
-	if err != nil {
-		diags.AddError(
-			"ACResource: create ##: Error creating AC",
-			"Create: Could not create AC, unexpected error: "+err.Error(),
-		)
-		return
+	if !(plan.EmcOuterVID.IsNull()) {
+		cmd["emcOuterVID"] = plan.EmcOuterVID.ValueString()
 	}
-	tflog.Debug(ctx, "ACResource: create ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
 
-	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+	if !(plan.MaxPktLen.IsNull()) {
+		cmd["maxPktLen"] = plan.MaxPktLen.ValueInt64()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/ethernets/" + plan.EthernetId.ValueString() + "/acs/" + plan.AcId.ValueString()
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan. Adopting decides
+// between create and update only while applying.
+func (r *ACResource) plannedRequest(plan *ACResourceData, ctx context.Context, create bool) types.String {
+	if !create {
+		uri, cmd := r.updateRequest(plan)
+		return plannedRequestValue("PUT", uri, cmd)
+	}
+	if plan.AdoptExisting.ValueBool() {
+		return types.StringUnknown()
+	}
+	uri, cmd := r.createRequest(plan)
+	return plannedRequestValue("POST", uri, cmd)
+}
+
+// adopt takes over the AC with the planned ethernet and AC id if it already
+// exists on the module, updating it to match the plan. It returns false when
+// there is nothing to adopt and the AC must be created.
+func (r *ACResource) adopt(plan *ACResourceData, ctx context.Context, diags *diag.Diagnostics) bool {
+	href := "/ethernets/" + plan.EthernetId.ValueString() + "/acs/" + plan.AcId.ValueString()
+
+	exists, err := resourceExists(ctx, r.client, plan.N.ValueString(), href)
 	if err != nil {
 		diags.AddError(
-			"ACResource: create ##: Error Create LC",
-			"Create: Could not AC SetResourceId, unexpected error: "+err.Error(),
+			"ACResource: adopt ##: Error Adopt AC",
+			"Adopt: Could not look up existing AC, unexpected error: "+err.Error(),
 		)
-		return
+		return true
 	}
-	rep = content["rep"].(map[string]interface{})
-	aid := rep["aid"]
-	if aid != nil && len(aid.(string)) > 0 {
-		plan.Aid = types.StringValue(aid.(string))
-	} else {
-		plan.Aid = types.StringValue("")
+	if !exists {
+		return false
 	}
-	plan.DeviceId = types.StringValue(deviceId)
 
-	tflog.Debug(ctx, "ACResource: create ##", map[string]interface{}{"plan": plan})
+	tflog.Debug(ctx, "ACResource: adopt ## ", map[string]interface{}{"Device": plan.N.ValueString(), "href": href})
+
+	plan.Id = types.StringValue(plan.N.ValueString() + href)
+	r.update(plan, ctx, diags)
+	if diags.HasError() {
+		return true
+	}
+	r.read(plan, ctx, diags)
+	return true
 }
 
 func (r *ACResource) read(plan *ACResourceData, ctx context.Context, diags *diag.Diagnostics) {
@@ -418,31 +534,7 @@ func (r *ACResource) update(plan *ACResourceData, ctx context.Context, diags *di
 
 	tflog.Debug(ctx, "ACResource: update ## ", map[string]interface{}{"Acid": plan.AcId.ValueString(), "EthernetId": plan.EthernetId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.Capacity.IsNull()) {
-		cmd["capacity"] = plan.Capacity.ValueInt64()
-	}
-
-	if !(plan.Imc.IsNull()) {
-		cmd["imc"] = plan.Imc.ValueString()
-	}
-
-	if !(plan.ImcOuterVID.IsNull()) {
-		cmd["imcOuterVID"] = plan.ImcOuterVID.ValueString()
-	}
-
-	if !(plan.Emc.IsNull()) {
-		cmd["emc"] = plan.Emc.ValueString()
-	}
-
-	if !(plan.EmcOuterVID.IsNull()) {
-		cmd["emcOuterVID"] = plan.EmcOuterVID.ValueString()
-	}
-
-	if !(plan.MaxPktLen.IsNull()) {
-		cmd["maxPktLen"] = plan.MaxPktLen.ValueInt64()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		return
@@ -458,14 +550,9 @@ func (r *ACResource) update(plan *ACResourceData, ctx context.Context, diags *di
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/ethernets/" + plan.EthernetId.ValueString() + "/acs/" + plan.AcId.ValueString()
-	}
-
-	tflog.Debug(ctx, "ACResource: Update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resource-links" + href, "Input data": string(rb)})
+	tflog.Debug(ctx, "ACResource: Update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
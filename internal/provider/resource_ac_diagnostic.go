@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &ACDiagResource{}
 	_ resource.ResourceWithConfigure   = &ACDiagResource{}
 	_ resource.ResourceWithImportState = &ACDiagResource{}
+	_ resource.ResourceWithModifyPlan  = &ACDiagResource{}
 )
 
 // NewACDiagResource is a helper function to simplify the provider implementation.
@@ -35,13 +36,15 @@ type ACDiagResource struct {
 }
 
 type ACDiagResourceData struct {
-	Id         types.String `tfsdk:"id"`
-	N          types.String `tfsdk:"n"`
-	DeviceId   types.String `tfsdk:"deviceid"`
-	EthernetId types.String `tfsdk:"ethernetid"`
-	AcId       types.String `tfsdk:"acid"`
-	Aid        types.String `tfsdk:"aid"`
-	TermLB     types.String `tfsdk:"termlb"`
+	Id                 types.String `tfsdk:"id"`
+	N                  types.String `tfsdk:"n"`
+	DeviceId           types.String `tfsdk:"deviceid"`
+	EthernetId         types.String `tfsdk:"ethernetid"`
+	AcId               types.String `tfsdk:"acid"`
+	Aid                types.String `tfsdk:"aid"`
+	TermLB             types.String `tfsdk:"termlb"`
+	AllowTrafficImpact types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest     types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -85,6 +88,14 @@ func (r *ACDiagResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				Description: "term Loopback",
 				Optional:    true,
 			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -98,6 +109,13 @@ func (r *ACDiagResource) Configure(_ context.Context, req resource.ConfigureRequ
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs.
+func (r *ACDiagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, acDiagTrafficAttributes)
+}
+
 func (r ACDiagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data ACDiagResourceData
 
@@ -111,6 +129,7 @@ func (r ACDiagResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.read(&data, ctx, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
@@ -157,6 +176,7 @@ func (r ACDiagResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -263,11 +283,7 @@ func (r *ACDiagResource) update(plan *ACDiagResourceData, ctx context.Context, d
 
 	tflog.Debug(ctx, "ACDiagResource: create ## ", map[string]interface{}{"Acid": plan.AcId.ValueString(), "EthernetId": plan.EthernetId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.TermLB.IsNull()) {
-		cmd["termLB"] = plan.TermLB.ValueString()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		return
@@ -283,14 +299,9 @@ func (r *ACDiagResource) update(plan *ACDiagResourceData, ctx context.Context, d
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/ethernets/" + plan.EthernetId.ValueString() + "/acs/" + plan.AcId.ValueString()
-	}
-
-	tflog.Debug(ctx, "ACDiagResource: Update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resource-links" + href, "Input data": string(rb)})
+	tflog.Debug(ctx, "ACDiagResource: Update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -320,6 +331,31 @@ func (r *ACDiagResource) update(plan *ACDiagResourceData, ctx context.Context, d
 	tflog.Debug(ctx, "ACDiagResource: update ## ", map[string]interface{}{"plan": plan})
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *ACDiagResource) updateRequest(plan *ACDiagResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.TermLB.IsNull()) {
+		cmd["termLB"] = plan.TermLB.ValueString()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/ethernets/" + plan.EthernetId.ValueString() + "/acs/" + plan.AcId.ValueString()
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *ACDiagResource) plannedRequest(plan *ACDiagResourceData, ctx context.Context, create bool) types.String {
+	if create {
+		return types.StringNull()
+	}
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *ACDiagResource) delete(plan *ACDiagResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	href := after(plan.Id.ValueString(), "/")
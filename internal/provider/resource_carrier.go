@@ -5,10 +5,8 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
-	//"github.com/hashicorp/terraform-plugin-framework/attr"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -25,6 +23,7 @@ var (
 	_ resource.Resource                = &CarrierResource{}
 	_ resource.ResourceWithConfigure   = &CarrierResource{}
 	_ resource.ResourceWithImportState = &CarrierResource{}
+	_ resource.ResourceWithModifyPlan  = &CarrierResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -73,6 +72,8 @@ type CarrierResourceData struct {
 	HAllowedRxCDSCs         types.Int64  `tfsdk:"hallowedrxcdscs"`
 	AAllowedRxCDSCs         types.Int64  `tfsdk:"aallowedrxcdscs"`
 	Capabilities            types.Map    `tfsdk:"capabilities"`
+	AllowTrafficImpact      types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest          types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -233,6 +234,14 @@ func (r *CarrierResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -246,6 +255,13 @@ func (r *CarrierResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs.
+func (r *CarrierResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, false, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, carrierTrafficAttributes)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *CarrierResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 
@@ -257,6 +273,7 @@ func (r *CarrierResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	plan.PlannedRequest = appliedRequestValue(r.plannedRequest(&plan, ctx, true))
 	r.update(&plan, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &plan)
@@ -299,6 +316,7 @@ func (r CarrierResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
@@ -337,48 +355,7 @@ func (r *CarrierResource) update(plan *CarrierResourceData, ctx context.Context,
 
 	tflog.Debug(ctx, "CarrierResource: update ## ", map[string]interface{}{"LinePTPId": plan.LinePTPId.ValueString(), "Carrier": plan.CarrierId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.AllowedTxCDSCs.IsNull()) {
-		cmd["allowedTxCDSCs"] = plan.AllowedTxCDSCs.ValueInt64()
-	}
-
-	if !(plan.AllowedRxCDSCs.IsNull()) {
-		cmd["allowedRxCDSCs"] = plan.AllowedRxCDSCs.ValueInt64()
-	}
-
-	if !(plan.Modulation.IsNull()) {
-		cmd["modulation"] = plan.Modulation.ValueString()
-	}
-
-	if !(plan.ClientPortMode.IsNull()) {
-		cmd["ClientPortMode"] = plan.ClientPortMode.ValueString()
-	}
-
-	if !(plan.ConstellationFrequency.IsNull()) {
-		cmd["constellationFrequency"] = plan.ConstellationFrequency.ValueInt64()
-	}
-	if !(plan.BaudRate.IsNull()) {
-		cmd["baudRate"] = plan.BaudRate.ValueInt64()
-	}
-
-	if !(plan.MaxDSCs.IsNull()) {
-		cmd["maxDSCs"] = plan.MaxDSCs.ValueInt64()
-	}
-
-	if !(plan.MaxTxDSCs.IsNull()) {
-		cmd["maxTxDSCs"] = plan.MaxTxDSCs.ValueInt64()
-	}
-
-	if !(plan.TxCLPtarget.IsNull()) {
-		cmd["txCLPtarget"] = plan.TxCLPtarget.ValueInt64()
-	}
-	if !(plan.FecIterations.IsNull()) {
-		cmd["fecIterations"] = plan.FecIterations.ValueString()
-	}
-	if !(plan.AdvLineCtrl.IsNull()) {
-		cmd["advLineCtrl"] = plan.AdvLineCtrl.ValueString()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		return
@@ -394,14 +371,9 @@ func (r *CarrierResource) update(plan *CarrierResourceData, ctx context.Context,
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString()
-	}
-
-	tflog.Debug(ctx, "CarrierResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
+	tflog.Debug(ctx, "CarrierResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -480,6 +452,65 @@ func (r *CarrierResource) update(plan *CarrierResourceData, ctx context.Context,
 
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *CarrierResource) updateRequest(plan *CarrierResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.AllowedTxCDSCs.IsNull()) {
+		cmd["allowedTxCDSCs"] = plan.AllowedTxCDSCs.ValueInt64()
+	}
+
+	if !(plan.AllowedRxCDSCs.IsNull()) {
+		cmd["allowedRxCDSCs"] = plan.AllowedRxCDSCs.ValueInt64()
+	}
+
+	if !(plan.Modulation.IsNull()) {
+		cmd["modulation"] = plan.Modulation.ValueString()
+	}
+
+	if !(plan.ClientPortMode.IsNull()) {
+		cmd["ClientPortMode"] = plan.ClientPortMode.ValueString()
+	}
+
+	if !(plan.ConstellationFrequency.IsNull()) {
+		cmd["constellationFrequency"] = plan.ConstellationFrequency.ValueInt64()
+	}
+	if !(plan.BaudRate.IsNull()) {
+		cmd["baudRate"] = plan.BaudRate.ValueInt64()
+	}
+
+	if !(plan.MaxDSCs.IsNull()) {
+		cmd["maxDSCs"] = plan.MaxDSCs.ValueInt64()
+	}
+
+	if !(plan.MaxTxDSCs.IsNull()) {
+		cmd["maxTxDSCs"] = plan.MaxTxDSCs.ValueInt64()
+	}
+
+	if !(plan.TxCLPtarget.IsNull()) {
+		cmd["txCLPtarget"] = plan.TxCLPtarget.ValueInt64()
+	}
+	if !(plan.FecIterations.IsNull()) {
+		cmd["fecIterations"] = plan.FecIterations.ValueString()
+	}
+	if !(plan.AdvLineCtrl.IsNull()) {
+		cmd["advLineCtrl"] = plan.AdvLineCtrl.ValueString()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString()
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *CarrierResource) plannedRequest(plan *CarrierResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *CarrierResource) read(state *CarrierResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if state.LinePTPId.IsNull() || state.CarrierId.IsNull() {
@@ -530,8 +561,8 @@ func (r *CarrierResource) read(state *CarrierResourceData, ctx context.Context,
 		switch k {
 		case "aid":
 			state.Aid = types.StringValue(v.(string))
-		case "FecIterations":
-			if !(state.FecIterations.IsNull()) {
+		case "fecIterations":
+			if !(state.FecIterations.IsNull()) && !hostOwned(content, k) {
 				state.FecIterations = types.StringValue(v.(string))
 			}
 		case "advLineCtrl":
@@ -539,41 +570,41 @@ func (r *CarrierResource) read(state *CarrierResourceData, ctx context.Context,
 				state.AdvLineCtrl = types.StringValue(v.(string))
 			}
 		case "modulation":
-			if !(state.Modulation.IsNull()) {
+			if !(state.Modulation.IsNull()) && !hostOwned(content, k) {
 				state.Modulation = types.StringValue(v.(string))
 			}
 		case "clientPortMode":
-			if !(state.ClientPortMode.IsNull()) {
+			if !(state.ClientPortMode.IsNull()) && !hostOwned(content, k) {
 				state.ClientPortMode = types.StringValue(v.(string))
 			}
 		case "constellationFrequency":
-			if !(state.ConstellationFrequency.IsNull()) {
+			if !(state.ConstellationFrequency.IsNull()) && !hostOwned(content, k) {
 				state.ConstellationFrequency = types.Int64Value(int64(v.(float64)))
 			}
 		case "baudRate":
-			if !(state.BaudRate.IsNull()) {
+			if !(state.BaudRate.IsNull()) && !hostOwned(content, k) {
 				state.BaudRate = types.Int64Value(int64(v.(float64)))
 			}
 		case "maxDSCs":
-			if !(state.MaxDSCs.IsNull()) {
+			if !(state.MaxDSCs.IsNull()) && !hostOwned(content, k) {
 				state.MaxDSCs = types.Int64Value(int64(v.(float64)))
 			}
 		case "maxTxDSCs":
-			if !(state.MaxTxDSCs.IsNull()) {
+			if !(state.MaxTxDSCs.IsNull()) && !hostOwned(content, k) {
 				state.MaxTxDSCs = types.Int64Value(int64(v.(float64)))
 			}
 		case "spectralBandwidth":
 			state.SpectralBandwidth = types.Int64Value(int64(v.(float64)))
 		case "txCLPtarget":
-			if !(state.TxCLPtarget.IsNull()) {
+			if !(state.TxCLPtarget.IsNull()) && !hostOwned(content, k) {
 				state.TxCLPtarget = types.Int64Value(int64(v.(float64)))
 			}
 		case "allowedTxCDSCs":
-			if !(state.AllowedTxCDSCs.IsNull()) {
+			if !(state.AllowedTxCDSCs.IsNull()) && !hostOwned(content, k) {
 				state.AllowedTxCDSCs = types.Int64Value(int64(v.(float64)))
 			}
 		case "allowedRxCDSCs":
-			if !(state.AllowedRxCDSCs.IsNull()) {
+			if !(state.AllowedRxCDSCs.IsNull()) && !hostOwned(content, k) {
 				state.AllowedRxCDSCs = types.Int64Value(int64(v.(float64)))
 			}
 		case "hModulation":
@@ -611,11 +642,9 @@ func (r *CarrierResource) read(state *CarrierResourceData, ctx context.Context,
 		case "aAllowedRxCDSCs":
 			state.AAllowedRxCDSCs = types.Int64Value(int64(v.(float64)))
 		case "capabilities":
-			capMap := make(map[string]attr.Value)
-			for k,v2 := range v.(map[string]interface{})  {
-				capMap[k] = types.StringValue(v2.(string))
+			if capabilities, ok := v.(map[string]interface{}); ok {
+				state.Capabilities = capabilitiesValue(capabilities)
 			}
-			state.Capabilities, _ = types.MapValue(types.StringType, capMap)
 		}
 	}
 	tflog.Debug(ctx, "CarrierResource: read ## ", map[string]interface{}{"state": state})
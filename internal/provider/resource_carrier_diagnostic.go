@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &CarrierDiagResource{}
 	_ resource.ResourceWithConfigure   = &CarrierDiagResource{}
 	_ resource.ResourceWithImportState = &CarrierDiagResource{}
+	_ resource.ResourceWithModifyPlan  = &CarrierDiagResource{}
 )
 
 // NewCarrierDiagResource is a helper function to simplify the provider implementation.
@@ -35,13 +36,16 @@ type CarrierDiagResource struct {
 }
 
 type CarrierDiagResourceData struct {
-	Id             types.String `tfsdk:"id"`
-	N              types.String `tfsdk:"n"`
-	DeviceId       types.String `tfsdk:"deviceid"`
-	LinePTPId      types.String `tfsdk:"lineptpid"`
-	CarrierId      types.String `tfsdk:"carrierid"`
-	TermLB         types.String `tfsdk:"termlb"`
-	TermLBDuration types.Int64  `tfsdk:"termlbduration"`
+	Id                 types.String `tfsdk:"id"`
+	N                  types.String `tfsdk:"n"`
+	DeviceId           types.String `tfsdk:"deviceid"`
+	LinePTPId          types.String `tfsdk:"lineptpid"`
+	CarrierId          types.String `tfsdk:"carrierid"`
+	TermLB             types.String `tfsdk:"termlb"`
+	TermLBDuration     types.Int64  `tfsdk:"termlbduration"`
+	AutoRevertAfter    types.String `tfsdk:"auto_revert_after"`
+	AllowTrafficImpact types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest     types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -85,6 +89,18 @@ func (r *CarrierDiagResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "Term Loopback Duration",
 				Optional:    true,
 			},
+			"auto_revert_after": schema.StringAttribute{
+				Description: "Wait this long after enabling a loopback or PRBS, e.g. 10m, at most 1h, then check that the device cleared it and revert it if not. Apply holds until then; the state keeps the planned settings and the next refresh reports them cleared.",
+				Optional:    true,
+			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -98,6 +114,15 @@ func (r *CarrierDiagResource) Configure(_ context.Context, req resource.Configur
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs and invalid
+// auto_revert_after.
+func (r *CarrierDiagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, carrierDiagTrafficAttributes)
+	validateAutoRevertAfter(ctx, req, resp)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 
 func (r CarrierDiagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -112,7 +137,12 @@ func (r CarrierDiagResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -158,7 +188,12 @@ func (r CarrierDiagResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -176,6 +211,13 @@ func (r CarrierDiagResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	// Restore the non-loopback, non-PRBS state.
+	uri, cmd := r.updateRequest(&data)
+	revertDiagnostic(ctx, r.client, data.N.ValueString(), uri, cmd, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.State.RemoveResource(ctx)
 }
 
@@ -195,15 +237,7 @@ func (r *CarrierDiagResource) update(plan *CarrierDiagResourceData, ctx context.
 
 	tflog.Debug(ctx, "CarrierDiagResource: update ## ", map[string]interface{}{"LinePTPId": plan.LinePTPId.ValueString(), "Carrier": plan.CarrierId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.TermLB.IsNull()) {
-		cmd["termLB"] = plan.TermLB.ValueString()
-	}
-
-	if !(plan.TermLBDuration.IsNull()) {
-		cmd["termLBDuration"] = plan.TermLBDuration.ValueInt64()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		return
@@ -219,14 +253,9 @@ func (r *CarrierDiagResource) update(plan *CarrierDiagResourceData, ctx context.
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/diagnostic"
-	}
+	tflog.Debug(ctx, "CarrierDiagResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	tflog.Debug(ctx, "CarrierDiagResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
-
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources/lineptps/"+plan.LinePTPId.ValueString()+"/carriers/"+plan.CarrierId.ValueString()+"/diagnostic", rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -251,6 +280,32 @@ func (r *CarrierDiagResource) update(plan *CarrierDiagResourceData, ctx context.
 	}
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *CarrierDiagResource) updateRequest(plan *CarrierDiagResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.TermLB.IsNull()) {
+		cmd["termLB"] = plan.TermLB.ValueString()
+	}
+
+	if !(plan.TermLBDuration.IsNull()) {
+		cmd["termLBDuration"] = plan.TermLBDuration.ValueInt64()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/diagnostic"
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *CarrierDiagResource) plannedRequest(plan *CarrierDiagResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *CarrierDiagResource) read(plan *CarrierDiagResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if plan.LinePTPId.IsNull() || plan.CarrierId.IsNull() {
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &CfgResource{}
 	_ resource.ResourceWithConfigure   = &CfgResource{}
 	_ resource.ResourceWithImportState = &CfgResource{}
+	_ resource.ResourceWithModifyPlan  = &CfgResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -51,6 +52,8 @@ type CfgResourceData struct {
 	FactoryResetAction types.Bool   `tfsdk:"factoryresetaction"`
 	HId                types.String `tfsdk:"hid"`
 	HPortId            types.String `tfsdk:"hportid"`
+	AllowTrafficImpact types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest     types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the resource type name.
@@ -130,6 +133,14 @@ func (r *CfgResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Description: "Host Port ID",
 				Computed:    true,
 			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -143,6 +154,13 @@ func (r *CfgResource) Configure(_ context.Context, req resource.ConfigureRequest
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs.
+func (r *CfgResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, cfgTrafficAttributes)
+}
+
 func (r CfgResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data CfgResourceData
 
@@ -152,6 +170,7 @@ func (r CfgResource) Create(ctx context.Context, req resource.CreateRequest, res
 		return
 	}
 	tflog.Debug(ctx, "CfgResource: Create", map[string]interface{}{"CfgResourceData": data})
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
@@ -196,6 +215,7 @@ func (r CfgResource) Update(ctx context.Context, req resource.UpdateRequest, res
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -224,33 +244,7 @@ func (r *CfgResource) update(plan *CfgResourceData, ctx context.Context, diags *
 
 	// convert TF to Cfg json - required do to camel case not supported in TF
 	tflog.Debug(ctx, "CfgResource: createUpdate ## ")
-	var cmd = make(map[string]interface{})
-	if !(plan.ConfiguredRole.IsNull()) {
-		cmd["configuredRole"] = plan.ConfiguredRole.ValueString()
-	}
-
-	if !(plan.TrafficMode.IsNull()) {
-		cmd["trafficMode"] = plan.TrafficMode.ValueString()
-	}
-
-	if !(plan.Topology.IsNull()) {
-		cmd["topology"] = plan.Topology.ValueString()
-	}
-	if !(plan.N.IsNull()) {
-		cmd["n"] = plan.N.ValueString()
-	}
-
-	if !(plan.TcMode.IsNull()) {
-		cmd["tcMode"] = plan.TcMode.ValueBool()
-	}
-
-	if !(plan.RestartAction.IsNull()) {
-		cmd["restartAction"] = plan.RestartAction.ValueString()
-	}
-
-	if !(plan.FactoryResetAction.IsNull()) {
-		cmd["factoryResetAction"] = plan.FactoryResetAction.ValueBool()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	rb, err := json.Marshal(cmd)
 
@@ -264,12 +258,7 @@ func (r *CfgResource) update(plan *CfgResourceData, ctx context.Context, diags *
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/cfg"
-	}
-
-	body, deviceid, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources/cfg", rb)
+	body, deviceid, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -319,6 +308,45 @@ func (r *CfgResource) update(plan *CfgResourceData, ctx context.Context, diags *
 	tflog.Debug(ctx, "CfgResource: createUpdate ## ", map[string]interface{}{"deviceid": deviceid})
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *CfgResource) updateRequest(plan *CfgResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+	if !(plan.ConfiguredRole.IsNull()) {
+		cmd["configuredRole"] = plan.ConfiguredRole.ValueString()
+	}
+
+	if !(plan.TrafficMode.IsNull()) {
+		cmd["trafficMode"] = plan.TrafficMode.ValueString()
+	}
+
+	if !(plan.Topology.IsNull()) {
+		cmd["topology"] = plan.Topology.ValueString()
+	}
+	if !(plan.N.IsNull()) {
+		cmd["n"] = plan.N.ValueString()
+	}
+
+	if !(plan.TcMode.IsNull()) {
+		cmd["tcMode"] = plan.TcMode.ValueBool()
+	}
+
+	if !(plan.RestartAction.IsNull()) {
+		cmd["restartAction"] = plan.RestartAction.ValueString()
+	}
+
+	if !(plan.FactoryResetAction.IsNull()) {
+		cmd["factoryResetAction"] = plan.FactoryResetAction.ValueBool()
+	}
+
+	return "resources/cfg", cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *CfgResource) plannedRequest(plan *CfgResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *CfgResource) read(plan *CfgResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	tflog.Debug(ctx, "CfgResource: read ", map[string]interface{}{"deviceID": plan.N.ValueString(), "URL": "resources/cfg"})
@@ -365,11 +393,11 @@ func (r *CfgResource) read(plan *CfgResourceData, ctx context.Context, diags *di
 		case "serdesRate":
 			plan.SerdesRate = types.StringValue(v.(string))
 		case "configuredRole":
-			if !(plan.ConfiguredRole.IsNull()) {
+			if !(plan.ConfiguredRole.IsNull()) && !hostOwned(content, k) {
 				plan.ConfiguredRole = types.StringValue(v.(string))
 			}
 		case "trafficMode":
-			if !(plan.TrafficMode.IsNull()) {
+			if !(plan.TrafficMode.IsNull()) && !hostOwned(content, k) {
 				plan.TrafficMode = types.StringValue(v.(string))
 			}
 		case "hId":
@@ -377,7 +405,7 @@ func (r *CfgResource) read(plan *CfgResourceData, ctx context.Context, diags *di
 		case "hPortId":
 			plan.HPortId = types.StringValue(v.(string))
 		case "topology":
-			if !(plan.Topology.IsNull()) {
+			if !(plan.Topology.IsNull()) && !hostOwned(content, k) {
 				plan.Topology = types.StringValue(v.(string))
 			}
 		case "restartAction":
@@ -389,7 +417,7 @@ func (r *CfgResource) read(plan *CfgResourceData, ctx context.Context, diags *di
 				plan.ConfigState = types.StringValue(v.(string))
 			}
 		case "tcMode":
-			if !(plan.TcMode.IsNull()) {
+			if !(plan.TcMode.IsNull()) && !hostOwned(content, k) {
 				plan.TcMode = types.BoolValue(v.(bool))
 			}
 		case "factoryResetAction":
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &DSCResource{}
 	_ resource.ResourceWithConfigure   = &DSCResource{}
 	_ resource.ResourceWithImportState = &DSCResource{}
+	_ resource.ResourceWithModifyPlan  = &DSCResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -47,6 +48,7 @@ type DSCResourceData struct {
 	RxStatus    types.String `tfsdk:"rxstatus"`
 	RelativeDPO types.Int64  `tfsdk:"relativedpo"`
 	ConfigState    types.String `tfsdk:"configstate"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -62,6 +64,11 @@ func (r *DSCResource) Configure(_ context.Context, req resource.ConfigureRequest
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *DSCResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 // Schema defines the schema for the DSC resource.
 func (r *DSCResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
@@ -118,6 +125,10 @@ func (r *DSCResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Description: "configstate",
 				Computed:    true,
 			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -134,6 +145,7 @@ func (r DSCResource) Create(ctx context.Context, req resource.CreateRequest, res
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
@@ -178,6 +190,7 @@ func (r DSCResource) Update(ctx context.Context, req resource.UpdateRequest, res
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
@@ -215,11 +228,7 @@ func (r *DSCResource) update(plan *DSCResourceData, ctx context.Context, diags *
 	}
 	tflog.Debug(ctx, "DSCResource: update ## ", map[string]interface{}{"LinePTPId": plan.LinePTPId.ValueString(), "Carrier": plan.CarrierId.ValueString(), "DSCID": plan.DscId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.RelativeDPO.IsNull()) {
-		cmd["relativeDPO"] = plan.RelativeDPO.ValueInt64()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		return
@@ -235,14 +244,9 @@ func (r *DSCResource) update(plan *DSCResourceData, ctx context.Context, diags *
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/dscs/" + plan.DscId.ValueString()
-	}
-
-	tflog.Debug(ctx, "DSCResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
+	tflog.Debug(ctx, "DSCResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -287,6 +291,28 @@ func (r *DSCResource) update(plan *DSCResourceData, ctx context.Context, diags *
 	tflog.Debug(ctx, "DSCResource: update ## ", map[string]interface{}{"plan": plan})
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *DSCResource) updateRequest(plan *DSCResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.RelativeDPO.IsNull()) {
+		cmd["relativeDPO"] = plan.RelativeDPO.ValueInt64()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/dscs/" + plan.DscId.ValueString()
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *DSCResource) plannedRequest(plan *DSCResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r DSCResource) read(state *DSCResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if state.LinePTPId.IsNull() || state.CarrierId.IsNull() || state.DscId.IsNull() {
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &DSCDiagResource{}
 	_ resource.ResourceWithConfigure   = &DSCDiagResource{}
 	_ resource.ResourceWithImportState = &DSCDiagResource{}
+	_ resource.ResourceWithModifyPlan  = &DSCDiagResource{}
 )
 
 // NewCarrierDiagResource is a helper function to simplify the provider implementation.
@@ -35,14 +36,17 @@ type DSCDiagResource struct {
 }
 
 type DSCDiagResourceData struct {
-	Id         types.String `tfsdk:"id"`
-	DeviceId   types.String `tfsdk:"deviceid"`
-	N          types.String `tfsdk:"n"`
-	LinePTPId  types.String `tfsdk:"lineptpid"`
-	CarrierId  types.String `tfsdk:"carrierid"`
-	DscId      types.String `tfsdk:"dscid"`
-	FacPRBSGen types.Bool   `tfsdk:"facprbsgen"`
-	FacPRBSMon types.Bool   `tfsdk:"facprbsmon"`
+	Id                 types.String `tfsdk:"id"`
+	DeviceId           types.String `tfsdk:"deviceid"`
+	N                  types.String `tfsdk:"n"`
+	LinePTPId          types.String `tfsdk:"lineptpid"`
+	CarrierId          types.String `tfsdk:"carrierid"`
+	DscId              types.String `tfsdk:"dscid"`
+	FacPRBSGen         types.Bool   `tfsdk:"facprbsgen"`
+	FacPRBSMon         types.Bool   `tfsdk:"facprbsmon"`
+	AutoRevertAfter    types.String `tfsdk:"auto_revert_after"`
+	AllowTrafficImpact types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest     types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -89,6 +93,18 @@ func (r *DSCDiagResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Description: "fac PRBS mon",
 				Optional:    true,
 			},
+			"auto_revert_after": schema.StringAttribute{
+				Description: "Wait this long after enabling a loopback or PRBS, e.g. 10m, at most 1h, then check that the device cleared it and revert it if not. Apply holds until then; the state keeps the planned settings and the next refresh reports them cleared.",
+				Optional:    true,
+			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -102,6 +118,15 @@ func (r *DSCDiagResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs and invalid
+// auto_revert_after.
+func (r *DSCDiagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, dscDiagTrafficAttributes)
+	validateAutoRevertAfter(ctx, req, resp)
+}
+
 func (r DSCDiagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data DSCDiagResourceData
 
@@ -114,7 +139,12 @@ func (r DSCDiagResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -158,7 +188,12 @@ func (r DSCDiagResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -176,6 +211,13 @@ func (r DSCDiagResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	// Restore the non-loopback, non-PRBS state.
+	uri, cmd := r.updateRequest(&data)
+	revertDiagnostic(ctx, r.client, data.N.ValueString(), uri, cmd, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.State.RemoveResource(ctx)
 }
 
@@ -195,14 +237,8 @@ func (r *DSCDiagResource) update(plan *DSCDiagResourceData, ctx context.Context,
 	}
 	tflog.Debug(ctx, "DSCDiagResource: update ## ", map[string]interface{}{"LinePTPId": plan.LinePTPId.ValueString(), "Carrier": plan.CarrierId.ValueString(), "DscId": plan.DscId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-	if !(plan.FacPRBSGen.IsNull()) {
-		cmd["facPRBSGen"] = plan.FacPRBSGen.ValueBool()
-	}
+	uri, cmd := r.updateRequest(plan)
 
-	if !(plan.FacPRBSMon.IsNull()) {
-		cmd["facPRBSMon"] = plan.FacPRBSMon.ValueBool()
-	}
 	if len(cmd) == 0. {
 		return
 	}
@@ -217,14 +253,9 @@ func (r *DSCDiagResource) update(plan *DSCDiagResourceData, ctx context.Context,
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/dscs/" + plan.DscId.ValueString() + "/diagnostic"
-	}
+	tflog.Debug(ctx, "DSCDiagResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	tflog.Debug(ctx, "DSCDiagResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
-
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources/lineptps/"+plan.LinePTPId.ValueString()+"/carriers/"+plan.CarrierId.ValueString()+"/dscs/"+plan.DscId.ValueString()+"/diagnostic", rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -250,6 +281,30 @@ func (r *DSCDiagResource) update(plan *DSCDiagResourceData, ctx context.Context,
 	tflog.Debug(ctx, "DSCDiagResource: update ## ", map[string]interface{}{"plan": plan})
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *DSCDiagResource) updateRequest(plan *DSCDiagResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+	if !(plan.FacPRBSGen.IsNull()) {
+		cmd["facPRBSGen"] = plan.FacPRBSGen.ValueBool()
+	}
+
+	if !(plan.FacPRBSMon.IsNull()) {
+		cmd["facPRBSMon"] = plan.FacPRBSMon.ValueBool()
+	}
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/dscs/" + plan.DscId.ValueString() + "/diagnostic"
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *DSCDiagResource) plannedRequest(plan *DSCDiagResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r DSCDiagResource) read(state *DSCDiagResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if state.LinePTPId.IsNull() || state.CarrierId.IsNull() || state.DscId.IsNull() {
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &DSCGResource{}
 	_ resource.ResourceWithConfigure   = &DSCGResource{}
 	_ resource.ResourceWithImportState = &DSCGResource{}
+	_ resource.ResourceWithModifyPlan  = &DSCGResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -47,6 +48,8 @@ type DSCGResourceData struct {
 	IdleCDSCs types.List   `tfsdk:"idlecdscs"`
 	DscgCtrl  types.Int64  `tfsdk:"dscgctrl"`
 	ConfigState    types.String `tfsdk:"configstate"`
+	AdoptExisting  types.Bool   `tfsdk:"adopt_existing"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
 }
 
 // Metadata returns the data source type name.
@@ -83,7 +86,8 @@ func (r *DSCGResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Optional:    true,
 			},
 			"aid": schema.StringAttribute{
-				Description: "aid",
+				Description: "aid, with adopt_existing and no dscgid the aid of the DSCG to adopt",
+				Optional:    true,
 				Computed:    true,
 			},
 			"dscgid": schema.StringAttribute{
@@ -107,6 +111,14 @@ func (r *DSCGResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Description: "configState",
 				Computed:    true,
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "Take over a DSCG that already exists on the carrier instead of failing the create, found by dscgid or else aid.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. An update deletes the DSCG and creates it again, its planned_request is the list of the DELETE and the POST. Unknown while the configuration has unknown values or adopt_existing is set.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -119,6 +131,11 @@ func (r *DSCGResource) Configure(_ context.Context, req resource.ConfigureReques
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *DSCGResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 func (r DSCGResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data DSCGResourceData
 
@@ -129,6 +146,7 @@ func (r DSCGResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.create(&data, ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -178,6 +196,7 @@ func (r DSCGResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.create(&data, ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -220,6 +239,94 @@ func (r DSCGResource) create(plan *DSCGResourceData, ctx context.Context, diags
 
 	tflog.Debug(ctx, "DSCGResource: create ## ", map[string]interface{}{"LinePTPId": plan.LinePTPId.ValueString(), "Carrier": plan.CarrierId.ValueString()})
 
+	if plan.AdoptExisting.ValueBool() && r.adopt(plan, ctx, diags) {
+		return
+	}
+
+	uri, cmd := r.createRequest(plan, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+
+	rb, err := json.Marshal(cmd)
+
+	if err != nil {
+		diags.AddError(
+			"DSCGResource: create ##: Error Create DSCG",
+			"Create: Could not Marshal DSCG, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "DSCGResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
+
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", uri, rb)
+
+	tflog.Debug(ctx, "DSCGResource: create ##  ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+
+	if err != nil {
+		diags.AddError(
+			"DSCGResource: create ##: Error Create DSCG",
+			"Create: Could not POST DSCG, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.DeviceId = types.StringValue(deviceId)
+	href := createdHref(body, uri, plan.DscgId.ValueString())
+	defer rollbackOnError(ctx, r.client, plan.N.ValueString(), diags, &plan.Id, func() string { return href })
+	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+
+	if err != nil {
+		diags.AddError(
+			"DSCGResource: create ##: Error Create DSCG",
+			"Create: Could not SetResourceId , unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	rep1, ok := content["rep"].(map[string]interface{})
+	if !ok {
+		diags.AddError(
+			"DSCGResource: create ##: Error Create DSCG",
+			"Create: Could not create DSCG, no rep data in response: "+string(body),
+		)
+		return
+	}
+	aid := rep1["aid"]
+	if aid != nil {
+		plan.Aid = types.StringValue(aid.(string))
+	} 
+
+	tflog.Debug(ctx, "DSCGResource: create ## ", map[string]interface{}{"plan": plan})
+}
+
+// createRequest returns the device command uri and body create POSTs for the plan.
+func (r DSCGResource) createRequest(plan *DSCGResourceData, ctx context.Context, diags *diag.Diagnostics) (string, map[string]interface{}) {
+	rep := r.rep(plan, ctx, diags)
+	if diags.HasError() {
+		return "", nil
+	}
+
+	var cmd = make(map[string]interface{})
+
+	cmd["rep"] = rep
+
+	var ifs []string
+	ifs = append(ifs, "oic.if.baseline", "oic.if.rw", "oic.if.delete")
+	cmd["if"] = ifs
+	var rt []string
+	rt = append(rt, "xr.carrier.dscg")
+	cmd["rt"] = rt
+	var p = make(map[string]int)
+	p["bm"] = 3
+	cmd["p"] = p
+
+	return "resource-links/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/dscgs", cmd
+}
+
+// rep returns the DSCG fields set in the plan, as the device expects them.
+func (r DSCGResource) rep(plan *DSCGResourceData, ctx context.Context, diags *diag.Diagnostics) map[string]interface{} {
 	var rep = make(map[string]interface{})
 
 	if !(plan.RxCDSCs.IsNull()) {
@@ -230,7 +337,7 @@ func (r DSCGResource) create(plan *DSCGResourceData, ctx context.Context, diags
 				"DSCGResource: create ##: Error Create DSCG",
 				"Create: Could not Create DSCG, RxCDSCs is invalid "+plan.RxCDSCs.String(),
 			)
-			return
+			return nil
 		}
 		rxCDSCs := setBits(rxCDSCList)
 		rep["rxCDSCs"] = rxCDSCs
@@ -244,7 +351,7 @@ func (r DSCGResource) create(plan *DSCGResourceData, ctx context.Context, diags
 				"DSCGResource: create ##: Error Create DSCG",
 				"Create: Could not Create DSCG, TxCDSCs is invalid "+plan.TxCDSCs.String(),
 			)
-			return
+			return nil
 		}
 		txCDSCs := setBits(txCDSCList)
 		rep["txCDSCs"] = txCDSCs
@@ -258,7 +365,7 @@ func (r DSCGResource) create(plan *DSCGResourceData, ctx context.Context, diags
 				"DSCGResource: create ##: Error Create DSCG",
 				"Create: Could not Create DSCG, TxCDSCs is invalid "+plan.TxCDSCs.String(),
 			)
-			return
+			return nil
 		}
 		idleCDSCs := setBits(idleCDSCList)
 		rep["idleCDSCs"] = idleCDSCs
@@ -268,66 +375,138 @@ func (r DSCGResource) create(plan *DSCGResourceData, ctx context.Context, diags
 		rep["dscgCtrl"] = plan.DscgCtrl.ValueInt64()
 	}
 
-	var cmd = make(map[string]interface{})
-
-	cmd["rep"] = rep
-
-	var ifs []string
-	ifs = append(ifs, "oic.if.baseline", "oic.if.rw", "oic.if.delete")
-	cmd["if"] = ifs
-	var rt []string
-	rt = append(rt, "xr.carrier.dscg")
-	cmd["rt"] = rt
-	var p = make(map[string]int)
-	p["bm"] = 3
-	cmd["p"] = p
+	return rep
+}
 
-	rb, err := json.Marshal(cmd)
+// plannedRequest returns the planned_request of the plan. Update deletes the
+// DSCG and creates it again, so its planned_request lists the DELETE and the
+// POST. Adopting decides whether create sends anything only while applying.
+func (r DSCGResource) plannedRequest(plan *DSCGResourceData, ctx context.Context, create bool) types.String {
+	if create && plan.AdoptExisting.ValueBool() {
+		return types.StringUnknown()
+	}
+	var diags diag.Diagnostics
+	uri, cmd := r.createRequest(plan, ctx, &diags)
+	if diags.HasError() {
+		return types.StringUnknown()
+	}
+	if create {
+		return plannedRequestValue("POST", uri, cmd)
+	}
+	return plannedRequestsValue(
+		plannedRequest{Method: "DELETE", Href: "resource-links" + after(plan.Id.ValueString(), "/")},
+		plannedRequest{Method: "POST", Href: uri, Body: cmd},
+	)
+}
 
+// adopt takes over the DSCG with the planned line PTP, carrier and DSCG id,
+// or else aid, if it already exists on the module, updating it to match the
+// plan. It returns false when there is nothing to adopt and the DSCG must be
+// created.
+func (r DSCGResource) adopt(plan *DSCGResourceData, ctx context.Context, diags *diag.Diagnostics) bool {
+	href, err := r.lookupHref(plan, ctx)
 	if err != nil {
 		diags.AddError(
-			"DSCGResource: create ##: Error Create DSCG",
-			"Create: Could not Marshal DSCG, unexpected error: "+err.Error(),
+			"DSCGResource: adopt ##: Error Adopt DSCG",
+			"Adopt: Could not look up existing DSCG, unexpected error: "+err.Error(),
 		)
-		return
+		return true
+	}
+	if len(href) == 0 {
+		return false
 	}
 
-	tflog.Debug(ctx, "DSCGResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resource-links/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString() + "/dscgs", "Input data": string(rb)})
+	tflog.Debug(ctx, "DSCGResource: adopt ## ", map[string]interface{}{"Device": plan.N.ValueString(), "href": href})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", "resource-links/lineptps/"+plan.LinePTPId.ValueString()+"/carriers/"+plan.CarrierId.ValueString()+"/dscgs", rb)
+	plan.Id = types.StringValue(plan.N.ValueString() + href)
 
-	tflog.Debug(ctx, "DSCGResource: create ##  ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+	// The DSCG is adopted with the planned values, as if it had been created.
+	uri, cmd := r.adoptRequest(plan, href, ctx, diags)
+	if diags.HasError() {
+		return true
+	}
+	if len(cmd) > 0 {
+		rb, err := json.Marshal(cmd)
+		if err != nil {
+			diags.AddError(
+				"DSCGResource: adopt ##: Error Adopt DSCG",
+				"Adopt: Could not Marshal DSCG, unexpected error: "+err.Error(),
+			)
+			return true
+		}
 
-	if err != nil {
-		diags.AddError(
-			"DSCGResource: create ##: Error Create DSCG",
-			"Create: Could not POST DSCG, unexpected error: "+err.Error(),
-		)
-		return
+		tflog.Debug(ctx, "DSCGResource: adopt ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "rb": string(rb)})
+
+		_, _, err = r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
+		if err != nil {
+			diags.AddError(
+				"DSCGResource: adopt ##: Error Adopt DSCG",
+				"Adopt: Could not update DSCG "+href+", unexpected error: "+err.Error(),
+			)
+			return true
+		}
 	}
 
-	plan.DeviceId = types.StringValue(deviceId)
-	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+	r.read(plan, ctx, diags)
+	return true
+}
 
+// adoptRequest returns the device command uri and body adopt PUTs to the
+// DSCG at href for the plan, only the fields set in the plan.
+func (r DSCGResource) adoptRequest(plan *DSCGResourceData, href string, ctx context.Context, diags *diag.Diagnostics) (string, map[string]interface{}) {
+	return "resources" + href, r.rep(plan, ctx, diags)
+}
+
+// lookupHref returns the href of the DSCG of the carrier with the dscgid of
+// the plan, or else its aid, or an empty string if there is none.
+func (r DSCGResource) lookupHref(plan *DSCGResourceData, ctx context.Context) (string, error) {
+	carrier := "/lineptps/" + plan.LinePTPId.ValueString() + "/carriers/" + plan.CarrierId.ValueString()
+	if len(plan.DscgId.ValueString()) > 0 {
+		href := carrier + "/dscgs/" + plan.DscgId.ValueString()
+		exists, err := resourceExists(ctx, r.client, plan.N.ValueString(), href)
+		if err != nil || !exists {
+			return "", err
+		}
+		return href, nil
+	}
+	if len(plan.Aid.ValueString()) == 0 {
+		return "", nil
+	}
+
+	data, _, err := GetResource(ctx, r.client, plan.N.ValueString(), "resources"+carrier+"/dscgs")
 	if err != nil {
-		diags.AddError(
-			"DSCGResource: create ##: Error Create DSCG",
-			"Create: Could not SetResourceId , unexpected error: "+err.Error(),
-		)
-		return
+		if strings.Contains(err.Error(), "status: 404") {
+			return "", nil
+		}
+		return "", err
 	}
 
-	rep1 := content["rep"].(map[string]interface{})
-	aid := rep1["aid"]
-	if aid != nil {
-		plan.Aid = types.StringValue(aid.(string))
-	} 
+	resultData, _ := data["data"].(map[string]interface{})
+	content, _ := resultData["content"].(map[string]interface{})
+	links, _ := content["links"].([]interface{})
 
-	tflog.Debug(ctx, "DSCGResource: create ## ", map[string]interface{}{"plan": plan})
+	for _, v := range links {
+		dscgrec, _ := v.(map[string]interface{})
+		href, ok := dscgrec["href"].(string)
+		if !ok {
+			continue
+		}
+		data2, _, err := GetResource(ctx, r.client, plan.N.ValueString(), "resources"+href)
+		if err != nil {
+			return "", err
+		}
+		resultData2, _ := data2["data"].(map[string]interface{})
+		dscgDataRec, _ := resultData2["content"].(map[string]interface{})
+		if dscgDataRec["aid"] == plan.Aid.ValueString() {
+			return href, nil
+		}
+	}
+	return "", nil
 }
 
 func (r DSCGResource) read(state *DSCGResourceData, ctx context.Context, diags *diag.Diagnostics) {
-	if state.DscgId.IsNull() {
+	// A DSCG adopted by aid has its href but no dscgid.
+	if state.DscgId.IsNull() && len(after(state.Id.ValueString(), "/")) == 0 {
 		diags.AddError(
 			"DSCGResource: read ##: Error Read DSCG",
 			"Read: Could not Read DSCG, DSCG ID must specify",
@@ -455,7 +634,7 @@ func (r *DSCGResource) update(plan *DSCGResourceData, ctx context.Context, diags
 	cmd["rxCDSCs"] = rxCDSCs
 
 	var txCDSCList []int
-	diag = plan.RxCDSCs.ElementsAs(ctx, &txCDSCList, true)
+	diag = plan.TxCDSCs.ElementsAs(ctx, &txCDSCList, true)
 	if diag != nil && diag.HasError() {
 		diags.AddError(
 			"DSCGResource: create ##: Error Create DSCG",
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDSCGAdoptRequest(t *testing.T) {
+	list := func(v ...int64) types.List {
+		var elems []attr.Value
+		for _, e := range v {
+			elems = append(elems, types.Int64Value(e))
+		}
+		l, _ := types.ListValue(types.Int64Type, elems)
+		return l
+	}
+	nullList := types.ListNull(types.Int64Type)
+
+	tests := []struct {
+		name string
+		plan DSCGResourceData
+		want map[string]interface{}
+	}{
+		{
+			"tx and rx",
+			DSCGResourceData{TxCDSCs: list(1, 2), RxCDSCs: list(3), IdleCDSCs: nullList, DscgCtrl: types.Int64Null()},
+			map[string]interface{}{"txCDSCs": 6, "rxCDSCs": 8},
+		},
+		{
+			"only rx",
+			DSCGResourceData{TxCDSCs: nullList, RxCDSCs: list(1), IdleCDSCs: nullList, DscgCtrl: types.Int64Null()},
+			map[string]interface{}{"rxCDSCs": 2},
+		},
+		{
+			"only dscgctrl",
+			DSCGResourceData{TxCDSCs: nullList, RxCDSCs: nullList, IdleCDSCs: nullList, DscgCtrl: types.Int64Value(1)},
+			map[string]interface{}{"dscgCtrl": int64(1)},
+		},
+		{
+			"nothing",
+			DSCGResourceData{TxCDSCs: nullList, RxCDSCs: nullList, IdleCDSCs: nullList, DscgCtrl: types.Int64Null()},
+			map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			uri, cmd := DSCGResource{}.adoptRequest(&tt.plan, "/lineptps/1/carriers/1/dscgs/2", context.Background(), &diags)
+			if diags.HasError() {
+				t.Fatalf("adoptRequest() errors = %v", diags)
+			}
+			if uri != "resources/lineptps/1/carriers/1/dscgs/2" {
+				t.Errorf("adoptRequest() uri = %q", uri)
+			}
+			if !reflect.DeepEqual(cmd, tt.want) {
+				t.Errorf("adoptRequest() = %v, want %v", cmd, tt.want)
+			}
+		})
+	}
+}
+
+func TestDSCGPlannedRequest(t *testing.T) {
+	nullList := types.ListNull(types.Int64Type)
+	plan := DSCGResourceData{
+		Id:        types.StringValue("xr-hub/lineptps/1/carriers/1/dscgs/2"),
+		LinePTPId: types.StringValue("1"),
+		CarrierId: types.StringValue("1"),
+		TxCDSCs:   nullList,
+		RxCDSCs:   nullList,
+		IdleCDSCs: nullList,
+		DscgCtrl:  types.Int64Value(1),
+	}
+
+	created := DSCGResource{}.plannedRequest(&plan, context.Background(), true)
+	want := `{"method":"POST","href":"resource-links/lineptps/1/carriers/1/dscgs","body":{"if":["oic.if.baseline","oic.if.rw","oic.if.delete"],"p":{"bm":3},"rep":{"dscgCtrl":1},"rt":["xr.carrier.dscg"]}}`
+	if created.ValueString() != want {
+		t.Errorf("plannedRequest() of a create = %s, want %s", created.ValueString(), want)
+	}
+
+	updated := DSCGResource{}.plannedRequest(&plan, context.Background(), false)
+	want = `[{"method":"DELETE","href":"resource-links/lineptps/1/carriers/1/dscgs/2"},` + want + `]`
+	if updated.ValueString() != want {
+		t.Errorf("plannedRequest() of an update = %s, want %s", updated.ValueString(), want)
+	}
+}
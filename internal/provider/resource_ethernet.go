@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &EthernetResource{}
 	_ resource.ResourceWithConfigure   = &EthernetResource{}
 	_ resource.ResourceWithImportState = &EthernetResource{}
+	_ resource.ResourceWithModifyPlan  = &EthernetResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -50,6 +51,7 @@ type EthernetResourceData struct {
 	PortSpeed  types.Int64  `tfsdk:"portspeed"`
 	MaxPktLen  types.Int64  `tfsdk:"maxpktlen"`
 	ConfigState    types.String `tfsdk:"configstate"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
 }
 
 // Schema defines the schema for the  resource.
@@ -100,6 +102,10 @@ func (r *EthernetResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Description: "configstate",
 				Computed:    true,
 			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -112,6 +118,11 @@ func (r *EthernetResource) Configure(_ context.Context, req resource.ConfigureRe
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *EthernetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 func (r EthernetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data EthernetResourceData
 
@@ -125,6 +136,7 @@ func (r EthernetResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	//r.create(&data, ctx, &resp.Diagnostics)
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	//	data.Id = types.String{Value: data.N.Value}
@@ -170,6 +182,7 @@ func (r EthernetResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -207,14 +220,7 @@ func (r *EthernetResource) update(plan *EthernetResourceData, ctx context.Contex
 
 	tflog.Debug(ctx, "EthernetResource: update ## ", map[string]interface{}{"EthernetId": plan.EthernetId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.FecMode.IsNull()) {
-		cmd["fecMode"] = plan.FecMode.ValueString()
-	}
-	if !(plan.MaxPktLen.IsNull()) {
-		cmd["maxPktLen"] = plan.MaxPktLen.ValueInt64()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		tflog.Debug(ctx, "EthernetResource: update ## No Settings, Nothing to configure", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources/ethernets/" + plan.EthernetId.ValueString()})
@@ -231,14 +237,9 @@ func (r *EthernetResource) update(plan *EthernetResourceData, ctx context.Contex
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/ethernets/" + plan.EthernetId.ValueString()
-	}
+	tflog.Debug(ctx, "EthernetResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	tflog.Debug(ctx, "EthernetResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
-
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -270,6 +271,31 @@ func (r *EthernetResource) update(plan *EthernetResourceData, ctx context.Contex
 
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *EthernetResource) updateRequest(plan *EthernetResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.FecMode.IsNull()) {
+		cmd["fecMode"] = plan.FecMode.ValueString()
+	}
+	if !(plan.MaxPktLen.IsNull()) {
+		cmd["maxPktLen"] = plan.MaxPktLen.ValueInt64()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/ethernets/" + plan.EthernetId.ValueString()
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *EthernetResource) plannedRequest(plan *EthernetResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *EthernetResource) read(plan *EthernetResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if plan.EthernetId.IsNull() {
@@ -327,13 +353,15 @@ func (r *EthernetResource) read(plan *EthernetResourceData, ctx context.Context,
 				plan.FecType = types.StringValue(v.(string))
 			}
 		case "fecMode":
-			if !(plan.FecMode.IsNull()) {
+			if !(plan.FecMode.IsNull()) && !hostOwned(content, k) {
 				plan.FecMode = types.StringValue(v.(string))
 			}
 		case "portSpeed":
-			plan.PortSpeed = types.Int64Value(int64(v.(float64)))
+			if plan.PortSpeed.IsNull() || !hostOwned(content, k) {
+				plan.PortSpeed = types.Int64Value(int64(v.(float64)))
+			}
 		case "maxPktLen":
-			if !(plan.MaxPktLen.IsNull()) {
+			if !(plan.MaxPktLen.IsNull()) && !hostOwned(content, k) {
 				plan.MaxPktLen = types.Int64Value(int64(v.(float64)))
 			}
 		case "configState":
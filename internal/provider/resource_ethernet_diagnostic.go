@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &EthernetDiagResource{}
 	_ resource.ResourceWithConfigure   = &EthernetDiagResource{}
 	_ resource.ResourceWithImportState = &EthernetDiagResource{}
+	_ resource.ResourceWithModifyPlan  = &EthernetDiagResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -40,18 +41,21 @@ func (r *EthernetDiagResource) Metadata(_ context.Context, req resource.Metadata
 }
 
 type EthernetDiagResourceData struct {
-	Id             types.String `tfsdk:"id"`
-	N              types.String `tfsdk:"n"`
-	DeviceId       types.String `tfsdk:"deviceid"`
-	Aid            types.String `tfsdk:"aid"`
-	EthernetId     types.String `tfsdk:"ethernetid"`
-	TermLB         types.String `tfsdk:"termlb"`
-	TermLBDuration types.Int64  `tfsdk:"termlbduration"`
-	FacLB          types.String `tfsdk:"faclb"`
-	FacLBDuration  types.Int64  `tfsdk:"faclbduration"`
-	FacPRBSGen     types.Bool `tfsdk:"facprbsgen"`
-	FacPRBSMon     types.Bool `tfsdk:"facprbsmon"`
-	TermPRBSGen    types.Bool `tfsdk:"termprbsgen"`
+	Id                 types.String `tfsdk:"id"`
+	N                  types.String `tfsdk:"n"`
+	DeviceId           types.String `tfsdk:"deviceid"`
+	Aid                types.String `tfsdk:"aid"`
+	EthernetId         types.String `tfsdk:"ethernetid"`
+	TermLB             types.String `tfsdk:"termlb"`
+	TermLBDuration     types.Int64  `tfsdk:"termlbduration"`
+	FacLB              types.String `tfsdk:"faclb"`
+	FacLBDuration      types.Int64  `tfsdk:"faclbduration"`
+	FacPRBSGen         types.Bool   `tfsdk:"facprbsgen"`
+	FacPRBSMon         types.Bool   `tfsdk:"facprbsmon"`
+	TermPRBSGen        types.Bool   `tfsdk:"termprbsgen"`
+	AutoRevertAfter    types.String `tfsdk:"auto_revert_after"`
+	AllowTrafficImpact types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest     types.String `tfsdk:"planned_request"`
 }
 
 // Schema defines the schema for the EthernetDiag resource.
@@ -110,6 +114,18 @@ func (r *EthernetDiagResource) Schema(_ context.Context, _ resource.SchemaReques
 				Description: "termprbsgen",
 				Optional:    true,
 			},
+			"auto_revert_after": schema.StringAttribute{
+				Description: "Wait this long after enabling a loopback or PRBS, e.g. 10m, at most 1h, then check that the device cleared it and revert it if not. Apply holds until then; the state keeps the planned settings and the next refresh reports them cleared.",
+				Optional:    true,
+			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -122,6 +138,15 @@ func (r *EthernetDiagResource) Configure(_ context.Context, req resource.Configu
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs and invalid
+// auto_revert_after.
+func (r *EthernetDiagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, ethernetDiagTrafficAttributes)
+	validateAutoRevertAfter(ctx, req, resp)
+}
+
 func (r EthernetDiagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data EthernetDiagResourceData
 
@@ -134,7 +159,12 @@ func (r EthernetDiagResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -178,7 +208,12 @@ func (r EthernetDiagResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
@@ -194,6 +229,14 @@ func (r EthernetDiagResource) Delete(ctx context.Context, req resource.DeleteReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Restore the non-loopback, non-PRBS state.
+	uri, cmd := r.updateRequest(&data)
+	revertDiagnostic(ctx, r.client, data.N.ValueString(), uri, cmd, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.State.RemoveResource(ctx)
 }
 
@@ -214,35 +257,7 @@ func (r *EthernetDiagResource) update(plan *EthernetDiagResourceData, ctx contex
 
 	tflog.Debug(ctx, "EthernetDiagResource: update ## ", map[string]interface{}{"EthernetId": plan.EthernetId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.TermLB.IsNull()) {
-		cmd["termLB"] = plan.TermLB.ValueString()
-	}
-
-	if !(plan.TermLBDuration.IsNull()) {
-		cmd["termLBDuration"] = plan.TermLBDuration.ValueInt64()
-	}
-
-	if !(plan.FacLB.IsNull()) {
-		cmd["facLB"] = plan.FacLB.ValueString()
-	}
-
-	if !(plan.FacLBDuration.IsNull()) {
-		cmd["facLBDuration"] = plan.FacLBDuration.ValueInt64()
-	}
-
-	if !(plan.FacPRBSGen.IsNull()) {
-		cmd["facPRBSGen"] = plan.FacPRBSGen.ValueBool()
-	}
-
-	if !(plan.FacPRBSMon.IsNull()) {
-		cmd["facPRBSMon"] = plan.FacPRBSMon.ValueBool()
-	}
-
-	if !(plan.TermPRBSGen.IsNull()) {
-		cmd["termPRBSGen"] = plan.TermPRBSGen.ValueBool()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		tflog.Debug(ctx, "EthernetDiagResource: update ## No Settings, Nothing to Run Diagnostic", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources/ethernets/" + plan.EthernetId.ValueString() + "/diagnostic"})
@@ -259,14 +274,9 @@ func (r *EthernetDiagResource) update(plan *EthernetDiagResourceData, ctx contex
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/ethernets/" + plan.EthernetId.ValueString() + "/diagnostic"
-	}
-
-	tflog.Debug(ctx, "EthernetDiagResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
+	tflog.Debug(ctx, "EthernetDiagResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -296,6 +306,52 @@ func (r *EthernetDiagResource) update(plan *EthernetDiagResourceData, ctx contex
 	tflog.Debug(ctx, "EthernetDiagResource: update ## ", map[string]interface{}{"plan": plan})
 
 }
+
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *EthernetDiagResource) updateRequest(plan *EthernetDiagResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.TermLB.IsNull()) {
+		cmd["termLB"] = plan.TermLB.ValueString()
+	}
+
+	if !(plan.TermLBDuration.IsNull()) {
+		cmd["termLBDuration"] = plan.TermLBDuration.ValueInt64()
+	}
+
+	if !(plan.FacLB.IsNull()) {
+		cmd["facLB"] = plan.FacLB.ValueString()
+	}
+
+	if !(plan.FacLBDuration.IsNull()) {
+		cmd["facLBDuration"] = plan.FacLBDuration.ValueInt64()
+	}
+
+	if !(plan.FacPRBSGen.IsNull()) {
+		cmd["facPRBSGen"] = plan.FacPRBSGen.ValueBool()
+	}
+
+	if !(plan.FacPRBSMon.IsNull()) {
+		cmd["facPRBSMon"] = plan.FacPRBSMon.ValueBool()
+	}
+
+	if !(plan.TermPRBSGen.IsNull()) {
+		cmd["termPRBSGen"] = plan.TermPRBSGen.ValueBool()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/ethernets/" + plan.EthernetId.ValueString() + "/diagnostic"
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *EthernetDiagResource) plannedRequest(plan *EthernetDiagResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
 func (r EthernetDiagResource) read(state *EthernetDiagResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if state.EthernetId.IsNull() {
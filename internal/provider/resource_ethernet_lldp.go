@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &EthernetLLDPResource{}
 	_ resource.ResourceWithConfigure   = &EthernetLLDPResource{}
 	_ resource.ResourceWithImportState = &EthernetLLDPResource{}
+	_ resource.ResourceWithModifyPlan  = &EthernetLLDPResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -53,6 +54,7 @@ type EthernetLLDPResourceData struct {
 	FlushHostDb      types.Bool   `tfsdk:"flushhostdb"`
 	TooManyNeighbors types.Bool   `tfsdk:"toomanyneighbors"`
 	ConfigState    types.String   `tfsdk:"configstate"`
+	PlannedRequest   types.String `tfsdk:"planned_request"`
 }
 
 // Schema defines the schema for the  resource.
@@ -115,6 +117,10 @@ func (r *EthernetLLDPResource) Schema(_ context.Context, _ resource.SchemaReques
 				Description: "configState",
 				Computed:    true,
 			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -127,6 +133,11 @@ func (r *EthernetLLDPResource) Configure(_ context.Context, req resource.Configu
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *EthernetLLDPResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 func (r EthernetLLDPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data EthernetLLDPResourceData
 	diags := req.Config.Get(ctx, &data)
@@ -136,6 +147,7 @@ func (r EthernetLLDPResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
@@ -177,6 +189,7 @@ func (r EthernetLLDPResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -310,31 +323,8 @@ func (r *EthernetLLDPResource) update(plan *EthernetLLDPResourceData, ctx contex
 
 	tflog.Debug(ctx, "EthernetLLDPResource: update ## ", map[string]interface{}{"EthernetId": plan.EthernetId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.AdminStatus.IsNull()) {
-		cmd["adminStatus"] = plan.AdminStatus.ValueString()
-	}
-
-	if !(plan.GccFwd.IsNull()) {
-		cmd["gccFwd"] = plan.GccFwd.ValueBool()
-	}
-
-	if !(plan.HostRxDrop.IsNull()) {
-		cmd["hostRxDrop"] = plan.HostRxDrop.ValueBool()
-	}
-
-	if !(plan.TTLUsage.IsNull()) {
-		cmd["TTLUsage"] = plan.TTLUsage.ValueBool()
-	}
+	uri, cmd := r.updateRequest(plan)
 
-	if !(plan.ClrStats.IsNull()) {
-		cmd["clrStats"] = plan.ClrStats.ValueBool()
-	}
-
-	if !(plan.FlushHostDb.IsNull()) {
-		cmd["flushHostDb"] = plan.FlushHostDb.ValueBool()
-	}
 	if len(cmd) == 0. {
 		tflog.Debug(ctx, "EthernetLLDPResource: update ## No Settings, Nothing to configure", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources/ethernets/" + plan.EthernetId.ValueString() + "/lldp-cfg"})
 		return
@@ -350,14 +340,9 @@ func (r *EthernetLLDPResource) update(plan *EthernetLLDPResourceData, ctx contex
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/ethernets/" + plan.EthernetId.ValueString() + "/lldp-cfg"
-	}
-
-	tflog.Debug(ctx, "EthernetLLDPResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
+	tflog.Debug(ctx, "EthernetLLDPResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -394,3 +379,44 @@ func (r *EthernetLLDPResource) update(plan *EthernetLLDPResourceData, ctx contex
 	tflog.Debug(ctx, "EthernetLLDPResource: update ## ", map[string]interface{}{"plan": plan})
 
 }
+
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *EthernetLLDPResource) updateRequest(plan *EthernetLLDPResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.AdminStatus.IsNull()) {
+		cmd["adminStatus"] = plan.AdminStatus.ValueString()
+	}
+
+	if !(plan.GccFwd.IsNull()) {
+		cmd["gccFwd"] = plan.GccFwd.ValueBool()
+	}
+
+	if !(plan.HostRxDrop.IsNull()) {
+		cmd["hostRxDrop"] = plan.HostRxDrop.ValueBool()
+	}
+
+	if !(plan.TTLUsage.IsNull()) {
+		cmd["TTLUsage"] = plan.TTLUsage.ValueBool()
+	}
+
+	if !(plan.ClrStats.IsNull()) {
+		cmd["clrStats"] = plan.ClrStats.ValueBool()
+	}
+
+	if !(plan.FlushHostDb.IsNull()) {
+		cmd["flushHostDb"] = plan.FlushHostDb.ValueBool()
+	}
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/ethernets/" + plan.EthernetId.ValueString() + "/lldp-cfg"
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *EthernetLLDPResource) plannedRequest(plan *EthernetLLDPResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
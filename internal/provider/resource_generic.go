@@ -0,0 +1,572 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &GenericResource{}
+	_ resource.ResourceWithConfigure   = &GenericResource{}
+	_ resource.ResourceWithImportState = &GenericResource{}
+	_ resource.ResourceWithModifyPlan  = &GenericResource{}
+)
+
+const (
+	// PUT the body to an existing resource; nothing is deleted on destroy.
+	genericModeResources = "resources"
+	// POST the body to a collection and DELETE the created link on destroy.
+	genericModeResourceLinks = "resource-links"
+)
+
+// NewGenericResource is a helper function to simplify the provider implementation.
+func NewGenericResource() resource.Resource {
+	return &GenericResource{}
+}
+
+type GenericResource struct {
+	client *xrcm_pf.Client
+}
+
+type GenericResourceData struct {
+	Id             types.String `tfsdk:"id"`
+	N              types.String `tfsdk:"n"`
+	DeviceId       types.String `tfsdk:"deviceid"`
+	Href           types.String `tfsdk:"href"`
+	Mode           types.String `tfsdk:"mode"`
+	Body           types.String `tfsdk:"body"`
+	Content        types.String `tfsdk:"content"`
+	ContentMap     types.Map    `tfsdk:"content_map"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
+}
+
+// Metadata returns the resource type name.
+func (r *GenericResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_resource"
+}
+
+// Schema defines the schema for the resource.
+func (r *GenericResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages any device resource by href, for XR features not yet modelled by a dedicated resource",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Device name followed by the href of the managed resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"n": schema.StringAttribute{
+				Description: "XR Device Name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"deviceid": schema.StringAttribute{
+				Description: "device id",
+				Computed:    true,
+			},
+			"href": schema.StringAttribute{
+				Description: "Resource href, e.g. /cfg. In resource-links mode, the collection to create the resource in, e.g. /lineptps/1/carriers/1/dscgs.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mode": schema.StringAttribute{
+				Description: "resources (PUT, default) or resource-links (POST on create, DELETE on destroy).",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"body": schema.StringAttribute{
+				Description: "JSON request body. In resource-links mode, the keys under rep are the ones updated and checked for drift.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					jsonBodyPlanModifier{},
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "Resource content returned by the device, as JSON.",
+				Computed:    true,
+			},
+			"content_map": schema.MapAttribute{
+				Description: "Resource content flattened to dotted keys, e.g. rep.aid or capabilities.0.name.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *GenericResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*xrcm_pf.Client)
+}
+
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *GenericResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, false, r.plannedRequest)
+}
+
+func (r GenericResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GenericResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	tflog.Debug(ctx, "GenericResource: Create", map[string]interface{}{"GenericResourceData": data})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
+	r.create(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r GenericResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data GenericResourceData
+
+	diags := req.State.Get(ctx, &data)
+	tflog.Debug(ctx, "GenericResource: Read", map[string]interface{}{"GenericResourceData": data})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content := r.read(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Id.IsNull() {
+		resp.State = tfsdk.State{}
+		return
+	}
+
+	r.refreshBody(&data, content, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r GenericResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data GenericResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	tflog.Debug(ctx, "GenericResource: Update", map[string]interface{}{"GenericResourceData": data})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
+	r.update(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r GenericResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data GenericResourceData
+
+	diags := req.State.Get(ctx, &data)
+	tflog.Debug(ctx, "GenericResource: Delete", map[string]interface{}{"GenericResourceData": data})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.delete(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ImportState accepts an id of the form <device name><href>, e.g.
+// XR-SFO_1-1/cfg, for the default resources mode, or
+// resource-links:<device name><href> for a resource destroy deletes.
+func (r *GenericResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+	mode := types.StringNull()
+	if strings.HasPrefix(id, genericModeResourceLinks+":") {
+		id = strings.TrimPrefix(id, genericModeResourceLinks+":")
+		mode = types.StringValue(genericModeResourceLinks)
+	}
+
+	href := after(id, "/")
+	if len(href) == 0 {
+		resp.Diagnostics.AddError(
+			"GenericResource: import ##: Error Import Resource",
+			"Import: id must be the device name followed by the resource href, optionally prefixed by "+genericModeResourceLinks+":, got: "+req.ID,
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("n"), strings.TrimSuffix(id, href))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("mode"), mode)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("href"), href)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("body"), "{}")...)
+}
+
+func (r *GenericResource) create(plan *GenericResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	mode, cmd := r.request(plan, diags)
+	if diags.HasError() {
+		return
+	}
+
+	if mode == genericModeResources {
+		r.update(plan, ctx, diags)
+		return
+	}
+
+	tflog.Debug(ctx, "GenericResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resource-links" + plan.Href.ValueString(), "cmd": plan.Body.ValueString()})
+
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", "resource-links"+plan.Href.ValueString(), []byte(plan.Body.ValueString()))
+	if err != nil {
+		diags.AddError(
+			"GenericResource: create ##: Error Create Resource",
+			"Create: Could not POST "+plan.Href.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "GenericResource: create ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+
+	href := getResponseHref(body)
+	defer rollbackOnError(ctx, r.client, plan.N.ValueString(), diags, &plan.Id, func() string { return href })
+	if len(href) == 0 {
+		diags.AddError(
+			"GenericResource: create ##: Error Create Resource",
+			"Create: Could not find the href of the created resource in response: "+string(body),
+		)
+		return
+	}
+
+	plan.Id = types.StringValue(plan.N.ValueString() + href)
+	plan.DeviceId = types.StringValue(deviceId)
+
+	r.read(plan, ctx, diags)
+	if diags.HasError() || plan.Id.IsNull() {
+		if !diags.HasError() {
+			diags.AddError(
+				"GenericResource: create ##: Error Create Resource",
+				"Create: Could not read back created resource "+href,
+			)
+		}
+		return
+	}
+
+	tflog.Debug(ctx, "GenericResource: create ## ", map[string]interface{}{"plan": plan, "cmd": cmd})
+}
+
+func (r *GenericResource) update(plan *GenericResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	mode, cmd := r.request(plan, diags)
+	if diags.HasError() {
+		return
+	}
+
+	href, cmd := r.updateRequest(plan, mode, cmd)
+
+	rb, err := json.Marshal(cmd)
+	if err != nil {
+		diags.AddError(
+			"GenericResource: update ##: Error Update Resource",
+			"Update: Could not Marshal body, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "GenericResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
+
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	if err != nil {
+		diags.AddError(
+			"GenericResource: update ##: Error Update Resource",
+			"Update: Could not PUT "+href+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "GenericResource: update ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+
+	plan.Id = types.StringValue(plan.N.ValueString() + href)
+	plan.DeviceId = types.StringValue(deviceId)
+
+	r.read(plan, ctx, diags)
+}
+
+// updateRequest returns the href and body update PUTs for the plan. In
+// resource-links mode that is the rep of the body, at the created href.
+func (r *GenericResource) updateRequest(plan *GenericResourceData, mode string, cmd map[string]interface{}) (string, map[string]interface{}) {
+	href := plan.Href.ValueString()
+	if mode == genericModeResourceLinks {
+		href = after(plan.Id.ValueString(), "/")
+		if rep, ok := cmd["rep"]; ok {
+			cmd, _ = rep.(map[string]interface{})
+		}
+	}
+	return href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *GenericResource) plannedRequest(plan *GenericResourceData, ctx context.Context, create bool) types.String {
+	var diags diag.Diagnostics
+	mode, cmd := r.request(plan, &diags)
+	if diags.HasError() {
+		return types.StringUnknown()
+	}
+
+	if create && mode == genericModeResourceLinks {
+		return plannedRequestValue("POST", "resource-links"+plan.Href.ValueString(), cmd)
+	}
+
+	href, cmd := r.updateRequest(plan, mode, cmd)
+	return plannedRequestValue("PUT", "resources"+href, cmd)
+}
+
+// read refreshes the computed attributes from the device and returns the
+// resource content, or sets the id to null if the resource is gone.
+func (r *GenericResource) read(plan *GenericResourceData, ctx context.Context, diags *diag.Diagnostics) map[string]interface{} {
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		plan.Id = types.StringNull()
+		tflog.Debug(ctx, "GenericResource: read - href is empty", map[string]interface{}{"plan": plan})
+		return nil
+	}
+
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "GET", "resources"+href, nil)
+	if err != nil {
+		if !strings.Contains(err.Error(), "status: 404") {
+			diags.AddError(
+				"GenericResource: read ##: Error Read Resource",
+				"Read: Could not Get "+href+", unexpected error: "+err.Error(),
+			)
+			return nil
+		}
+		plan.Id = types.StringNull()
+		tflog.Debug(ctx, "GenericResource: read - not found ## 404", map[string]interface{}{"plan": plan})
+		return nil
+	}
+
+	tflog.Debug(ctx, "GenericResource: read ## ", map[string]interface{}{"response": string(body)})
+
+	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+	if err != nil {
+		diags.AddError(
+			"GenericResource: read ##: Error Read Resource",
+			"Read: Could not SetResourceId, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+
+	rb, err := json.Marshal(content)
+	if err != nil {
+		diags.AddError(
+			"GenericResource: read ##: Error Read Resource",
+			"Read: Could not Marshal content, unexpected error: "+err.Error(),
+		)
+		return nil
+	}
+
+	flat := make(map[string]attr.Value)
+	flattenContent("", content, flat)
+	contentMap, d := types.MapValue(types.StringType, flat)
+	diags.Append(d...)
+
+	plan.DeviceId = types.StringValue(deviceId)
+	plan.Content = types.StringValue(string(rb))
+	plan.ContentMap = contentMap
+
+	return content
+}
+
+// refreshBody writes the device values of the keys declared in body back into
+// body, so that drift on those keys shows up in the plan. Keys the body does
+// not declare are ignored, and the body is left untouched if nothing drifted.
+func (r *GenericResource) refreshBody(state *GenericResourceData, content map[string]interface{}, ctx context.Context, diags *diag.Diagnostics) {
+	mode, cmd := r.request(state, diags)
+	if diags.HasError() || content == nil {
+		return
+	}
+
+	declared := cmd
+	if mode == genericModeResourceLinks {
+		rep, ok := cmd["rep"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		declared = rep
+	}
+
+	drifted := false
+	for k, v := range declared {
+		actual, ok := content[k]
+		if ok && !reflect.DeepEqual(v, actual) {
+			tflog.Debug(ctx, "GenericResource: read - drift ## ", map[string]interface{}{"key": k, "declared": v, "actual": actual})
+			declared[k] = actual
+			drifted = true
+		}
+	}
+	if !drifted {
+		return
+	}
+
+	rb, err := json.Marshal(cmd)
+	if err != nil {
+		diags.AddError(
+			"GenericResource: read ##: Error Read Resource",
+			"Read: Could not Marshal body, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	state.Body = types.StringValue(string(rb))
+}
+
+// jsonBodyPlanModifier keeps the body of the state when the configured body is
+// the same JSON, whatever the order of its keys and its formatting.
+type jsonBodyPlanModifier struct{}
+
+func (m jsonBodyPlanModifier) Description(_ context.Context) string {
+	return "Ignores differences in the order of the keys and the formatting of the JSON body."
+}
+
+func (m jsonBodyPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m jsonBodyPlanModifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+	if jsonEqual(req.StateValue.ValueString(), req.PlanValue.ValueString()) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// jsonEqual reports whether a and b are the same JSON value.
+func jsonEqual(a string, b string) bool {
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// request validates mode and body and returns the mode and the decoded body.
+func (r *GenericResource) request(plan *GenericResourceData, diags *diag.Diagnostics) (string, map[string]interface{}) {
+	mode := genericModeResources
+	if !plan.Mode.IsNull() && len(plan.Mode.ValueString()) > 0 {
+		mode = plan.Mode.ValueString()
+	}
+	if mode != genericModeResources && mode != genericModeResourceLinks {
+		diags.AddError(
+			"GenericResource: Error Invalid Mode",
+			"mode must be "+genericModeResources+" or "+genericModeResourceLinks+", got: "+mode,
+		)
+		return mode, nil
+	}
+
+	var cmd = make(map[string]interface{})
+	if err := json.Unmarshal([]byte(plan.Body.ValueString()), &cmd); err != nil {
+		diags.AddError(
+			"GenericResource: Error Invalid Body",
+			"body must be a JSON object, unexpected error: "+err.Error(),
+		)
+		return mode, nil
+	}
+	return mode, cmd
+}
+
+func (r *GenericResource) delete(plan *GenericResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	if plan.Mode.ValueString() != genericModeResourceLinks {
+		// resources are owned by the device, there is nothing to delete.
+		return
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		return
+	}
+
+	tflog.Debug(ctx, "GenericResource: delete ## ", map[string]interface{}{"href": href})
+
+	body, _, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "DELETE", "resource-links"+href, nil)
+	if err != nil && !strings.Contains(err.Error(), "status: 404") {
+		diags.AddError(
+			"GenericResource: delete ##: Error Delete Resource",
+			"Delete: Could not Delete "+href+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "GenericResource: delete ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+}
+
+// flattenContent flattens nested JSON content into out, joining object keys
+// and list indices with dots. Scalars are stored as their JSON text, strings
+// without quotes.
+func flattenContent(prefix string, v interface{}, out map[string]attr.Value) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, e := range value {
+			flattenContent(joinKey(prefix, k), e, out)
+		}
+	case []interface{}:
+		for i, e := range value {
+			flattenContent(joinKey(prefix, fmt.Sprint(i)), e, out)
+		}
+	case string:
+		out[prefix] = types.StringValue(value)
+	case nil:
+		out[prefix] = types.StringValue("")
+	default:
+		rb, _ := json.Marshal(value)
+		out[prefix] = types.StringValue(string(rb))
+	}
+}
+
+func joinKey(prefix string, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &LCResource{}
 	_ resource.ResourceWithConfigure   = &LCResource{}
 	_ resource.ResourceWithImportState = &LCResource{}
+	_ resource.ResourceWithModifyPlan  = &LCResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -53,6 +54,8 @@ type LCResourceData struct {
 	RemoteModuleId types.String `tfsdk:"remotemoduleid"`
 	RemoteClientId types.String `tfsdk:"remoteclientid"`
 	ConfigState    types.String `tfsdk:"configstate"`
+	AdoptExisting  types.Bool   `tfsdk:"adopt_existing"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
 }
 
 // Schema defines the schema for the resource.
@@ -115,6 +118,14 @@ func (r *LCResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 				Description: "configState",
 				Computed:    true,
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "Take over an LC with the same client aid and dscg aid that already exists on the module instead of failing the create.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values or adopt_existing is set, null when nothing is sent.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -127,6 +138,11 @@ func (r *LCResource) Configure(_ context.Context, req resource.ConfigureRequest,
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *LCResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 func (r LCResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data LCResourceData
 
@@ -139,7 +155,11 @@ func (r LCResource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.create(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -181,6 +201,7 @@ func (r LCResource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -227,7 +248,88 @@ func (r *LCResource) create(plan *LCResourceData, ctx context.Context, diags *di
 
 	tflog.Debug(ctx, "LCResource: create ## ", map[string]interface{}{"ClientAid": plan.ClientAid.ValueString(), "DscgAid": plan.DscgAid.ValueString(), "LinePTPId": plan.LinePTPId.ValueString()})
 
+	if plan.AdoptExisting.ValueBool() && r.adopt(plan, ctx, diags) {
+		return
+	}
+
 	//create LC
+	uri, cmd := r.createRequest(plan)
+
+	rb, err := json.Marshal(cmd)
+
+	if err != nil {
+		diags.AddError(
+			"LCResource: create ##: Error Create LC",
+			"Create: Could not Marshal LC, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "LCResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "rb": string(rb)})
+
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", uri, rb)
+
+	if err != nil {
+		diags.AddError(
+			"LCResource: create ##: Error creating LC",
+			"Create: Could not create LC, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "LCResource: create ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+
+	href := getResponseHref(body)
+	// LCs are created without an id, find the LC by its aids if the response
+	// has no href.
+	defer rollbackOnError(ctx, r.client, plan.N.ValueString(), diags, &plan.Id, func() string {
+		if len(href) == 0 {
+			href, _ = r.lookupHref(plan, ctx)
+		}
+		return href
+	})
+
+	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+
+	if err != nil {
+		diags.AddError(
+			"LCResource: create ##: Error Create LC",
+			"Create: Could not create LC , unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	rep, ok := content["rep"].(map[string]interface{})
+	if !ok {
+		diags.AddError(
+			"LCResource: create ##: Error Create LC",
+			"Create: Could not create LC, no rep data in response: "+string(body),
+		)
+		return
+	}
+	if aid, ok := rep["aid"].(string); ok {
+		plan.Aid = types.StringValue(aid)
+	}
+
+	if lineAid, ok := rep["lineAid"].(string); ok {
+		plan.LineAid = types.StringValue(lineAid)
+	}
+
+	if remoteModuleId, ok := rep["remoteModuleId"].(string); ok {
+		plan.RemoteModuleId = types.StringValue(remoteModuleId)
+	}
+
+	if remoteClientId, ok := rep["remoteClientId"].(string); ok {
+		plan.RemoteClientId = types.StringValue(remoteClientId)
+	}
+
+	plan.DeviceId = types.StringValue(deviceId)
+
+	tflog.Debug(ctx, "LCResource: create ##", map[string]interface{}{"plan": plan})
+}
+
+// createRequest returns the device command uri and body create POSTs for the plan.
+func (r *LCResource) createRequest(plan *LCResourceData) (string, map[string]interface{}) {
 	var rep = make(map[string]interface{})
 
 	rep["clientAid"] = plan.ClientAid.ValueString()
@@ -256,68 +358,116 @@ func (r *LCResource) create(plan *LCResourceData, ctx context.Context, diags *di
 	p["bm"] = 3
 	cmd["p"] = p
 
-	if len(cmd) == 0. {
-		return
-	}
+	return "resource-links/lcs", cmd
+}
 
-	rb, err := json.Marshal(cmd)
+// plannedRequest returns the planned_request of the plan. Update sends
+// nothing, and adopting decides whether create sends anything only while
+// applying.
+func (r *LCResource) plannedRequest(plan *LCResourceData, ctx context.Context, create bool) types.String {
+	if !create {
+		return types.StringNull()
+	}
+	if plan.AdoptExisting.ValueBool() {
+		return types.StringUnknown()
+	}
+	uri, cmd := r.createRequest(plan)
+	return plannedRequestValue("POST", uri, cmd)
+}
 
+// adopt takes over the LC with the planned client aid and dscg aid if it
+// already exists on the module. It returns false when there is nothing to
+// adopt and the LC must be created.
+func (r *LCResource) adopt(plan *LCResourceData, ctx context.Context, diags *diag.Diagnostics) bool {
+	href, err := r.lookupHref(plan, ctx)
 	if err != nil {
 		diags.AddError(
-			"LCResource: create ##: Error Create LC",
-			"Create: Could not Marshal LC, unexpected error: "+err.Error(),
+			"LCResource: adopt ##: Error Adopt LC",
+			"Adopt: Could not GET LCs, unexpected error: "+err.Error(),
 		)
-		return
+		return true
+	}
+	if len(href) == 0 {
+		return false
 	}
 
-	tflog.Debug(ctx, "LCResource: create ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resource-links/lcs", "rb": string(rb)})
-
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "POST", "resource-links/lcs", rb)
+	tflog.Debug(ctx, "LCResource: adopt ## ", map[string]interface{}{"Device": plan.N.ValueString(), "href": href})
 
-	if err != nil {
-		diags.AddError(
-			"LCResource: create ##: Error creating LC",
-			"Create: Could not create LC, unexpected error: "+err.Error(),
-		)
-		return
-	}
+	plan.Id = types.StringValue(plan.N.ValueString() + href)
 
-	tflog.Debug(ctx, "LCResource: create ## ExecuteDeviceHttpCommand ..", map[string]interface{}{"response": string(body)})
+	// The LC is adopted with the planned values, as if it had been created.
+	uri, cmd := r.adoptRequest(plan, href)
+	if len(cmd) > 0 {
+		rb, err := json.Marshal(cmd)
+		if err != nil {
+			diags.AddError(
+				"LCResource: adopt ##: Error Adopt LC",
+				"Adopt: Could not Marshal LC, unexpected error: "+err.Error(),
+			)
+			return true
+		}
 
-	content, err := SetResourceId(plan.N.ValueString(), &plan.Id, body)
+		tflog.Debug(ctx, "LCResource: adopt ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "rb": string(rb)})
 
-	if err != nil {
-		diags.AddError(
-			"LCResource: create ##: Error Create LC",
-			"Create: Could not create LC , unexpected error: "+err.Error(),
-		)
-		return
+		_, _, err = r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
+		if err != nil {
+			diags.AddError(
+				"LCResource: adopt ##: Error Adopt LC",
+				"Adopt: Could not update LC "+href+", unexpected error: "+err.Error(),
+			)
+			return true
+		}
 	}
 
-	rep = content["rep"].(map[string]interface{})
-	aid := rep["aid"]
-	if aid != nil {
-		plan.Aid = types.StringValue(aid.(string))
-	}
+	r.read(plan, ctx, diags)
+	return true
+}
+
+// adoptRequest returns the device command uri and body adopt PUTs to the LC
+// at href for the plan.
+func (r *LCResource) adoptRequest(plan *LCResourceData, href string) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
 
-	lineAid := rep["lineAid"]
-	if lineAid != nil {
-		plan.LineAid = types.StringValue(lineAid.(string))
+	if !(plan.LcCtrl.IsNull()) {
+		cmd["lcCtrl"] = plan.LcCtrl.ValueInt64()
 	}
 
-	remoteModuleId := rep["remoteModuleId"]
-	if remoteModuleId != nil {
-		plan.RemoteModuleId = types.StringValue(remoteModuleId.(string))
+	if !(plan.Direction.IsNull()) {
+		cmd["direction"] = plan.Direction.ValueString()
 	}
 
-	remoteClientId := rep["remoteClientId"]
-	if remoteModuleId != nil {
-		plan.RemoteClientId = types.StringValue(remoteClientId.(string))
+	return "resources" + href, cmd
+}
+
+// lookupHref returns the href of the LC of the device with the clientAid and
+// dscgAid of the plan, or an empty string if there is none.
+func (r *LCResource) lookupHref(plan *LCResourceData, ctx context.Context) (string, error) {
+	data, _, err := GetResource(ctx, r.client, plan.N.ValueString(), "resources/lcs")
+	if err != nil {
+		return "", err
 	}
 
-	plan.DeviceId = types.StringValue(deviceId)
+	resultData, _ := data["data"].(map[string]interface{})
+	content, _ := resultData["content"].(map[string]interface{})
+	links, _ := content["links"].([]interface{})
 
-	tflog.Debug(ctx, "LCResource: create ##", map[string]interface{}{"plan": plan})
+	for _, v := range links {
+		lcrec, _ := v.(map[string]interface{})
+		href, ok := lcrec["href"].(string)
+		if !ok {
+			continue
+		}
+		data2, _, err := GetResource(ctx, r.client, plan.N.ValueString(), "resources"+href)
+		if err != nil {
+			return "", err
+		}
+		resultData2, _ := data2["data"].(map[string]interface{})
+		lcDataRec, _ := resultData2["content"].(map[string]interface{})
+		if lcDataRec["clientAid"] == plan.ClientAid.ValueString() && lcDataRec["dscgAid"] == plan.DscgAid.ValueString() {
+			return href, nil
+		}
+	}
+	return "", nil
 }
 
 func (r *LCResource) update(plan *LCResourceData, ctx context.Context, diags *diag.Diagnostics) {
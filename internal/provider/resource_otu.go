@@ -6,7 +6,7 @@ import (
 
 	//"strings"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -24,6 +24,7 @@ var (
 	_ resource.Resource                = &OTUResource{}
 	_ resource.ResourceWithConfigure   = &OTUResource{}
 	_ resource.ResourceWithImportState = &OTUResource{}
+	_ resource.ResourceWithModifyPlan  = &OTUResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -52,6 +53,7 @@ type OTUResourceData struct {
 	TxTTI       types.String `tfsdk:"txtti"`
 	ExpectedTTI types.String `tfsdk:"expectedtti"`
 	ConfigState    types.String `tfsdk:"configstate"`
+	PlannedRequest types.String `tfsdk:"planned_request"`
 }
 
 // Schema defines the schema for the resource.
@@ -106,6 +108,10 @@ func (r *OTUResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Description: "configstate",
 				Computed:    true,
 			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -118,6 +124,11 @@ func (r *OTUResource) Configure(_ context.Context, req resource.ConfigureRequest
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send.
+func (r *OTUResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+}
+
 func (r OTUResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data OTUResourceData
 
@@ -128,6 +139,7 @@ func (r OTUResource) Create(ctx context.Context, req resource.CreateRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
@@ -171,6 +183,7 @@ func (r OTUResource) Update(ctx context.Context, req resource.UpdateRequest, res
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -207,15 +220,7 @@ func (r *OTUResource) update(plan *OTUResourceData, ctx context.Context, diags *
 
 	tflog.Debug(ctx, "OTUResource: update ## ", map[string]interface{}{"OtuId": plan.OtuId.ValueString()})
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.TxTTI.IsNull()) {
-		cmd["txTTI"] = plan.TxTTI.ValueString()
-	}
-
-	if !(plan.ExpectedTTI.IsNull()) {
-		cmd["expectedTTI"] = plan.ExpectedTTI.ValueString()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		tflog.Debug(ctx, "OTUResource: update ## No Settings, Nothing to configure", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources/otus/" + plan.OtuId.ValueString()})
@@ -232,14 +237,9 @@ func (r *OTUResource) update(plan *OTUResourceData, ctx context.Context, diags *
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/otus/" + plan.OtuId.ValueString()
-	}
+	tflog.Debug(ctx, "OTUResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": uri, "Input data": string(rb)})
 
-	tflog.Debug(ctx, "OTUResource: update ## ", map[string]interface{}{"Device": plan.N.ValueString(), "URL": "resources" + href, "Input data": string(rb)})
-
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -286,6 +286,32 @@ func (r *OTUResource) update(plan *OTUResourceData, ctx context.Context, diags *
 	tflog.Debug(ctx, "OTUResource: update ## ", map[string]interface{}{"plan": plan})
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *OTUResource) updateRequest(plan *OTUResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.TxTTI.IsNull()) {
+		cmd["txTTI"] = plan.TxTTI.ValueString()
+	}
+
+	if !(plan.ExpectedTTI.IsNull()) {
+		cmd["expectedTTI"] = plan.ExpectedTTI.ValueString()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/otus/" + plan.OtuId.ValueString()
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *OTUResource) plannedRequest(plan *OTUResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *OTUResource) read(state *OTUResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if state.OtuId.IsNull() {
@@ -14,7 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 
 	//"github.com/hashicorp/terraform-plugin-framework/tfsdk"
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -25,6 +25,7 @@ var (
 	_ resource.Resource                = &OTUDiagResource{}
 	_ resource.ResourceWithConfigure   = &OTUDiagResource{}
 	_ resource.ResourceWithImportState = &OTUDiagResource{}
+	_ resource.ResourceWithModifyPlan  = &OTUDiagResource{}
 )
 
 // NewACResource is a helper function to simplify the provider implementation.
@@ -42,15 +43,18 @@ func (r *OTUDiagResource) Metadata(_ context.Context, req resource.MetadataReque
 }
 
 type OTUDiagResourceData struct {
-	Id         types.String `tfsdk:"id"`
-	N          types.String `tfsdk:"n"`
-	DeviceId   types.String `tfsdk:"deviceid"`
-	Aid        types.String `tfsdk:"aid"`
-	OtuId      types.String `tfsdk:"otuid"`
-	TermLB     types.String `tfsdk:"termlb"`
-	TermLBDuration  types.Int64  `tfsdk:"termlbduration"`
-	FacLB      types.String `tfsdk:"faclb"`
-	FacLBDuration  types.Int64  `tfsdk:"faclbduration"`
+	Id                 types.String `tfsdk:"id"`
+	N                  types.String `tfsdk:"n"`
+	DeviceId           types.String `tfsdk:"deviceid"`
+	Aid                types.String `tfsdk:"aid"`
+	OtuId              types.String `tfsdk:"otuid"`
+	TermLB             types.String `tfsdk:"termlb"`
+	TermLBDuration     types.Int64  `tfsdk:"termlbduration"`
+	FacLB              types.String `tfsdk:"faclb"`
+	FacLBDuration      types.Int64  `tfsdk:"faclbduration"`
+	AutoRevertAfter    types.String `tfsdk:"auto_revert_after"`
+	AllowTrafficImpact types.Bool   `tfsdk:"allow_traffic_impact"`
+	PlannedRequest     types.String `tfsdk:"planned_request"`
 }
 
 // Schema defines the schema for the resource.
@@ -101,6 +105,18 @@ func (r *OTUDiagResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Description: "term Loopback Duration",
 				Optional:    true,
 			},
+			"auto_revert_after": schema.StringAttribute{
+				Description: "Wait this long after enabling a loopback or PRBS, e.g. 10m, at most 1h, then check that the device cleared it and revert it if not. Apply holds until then; the state keeps the planned settings and the next refresh reports them cleared.",
+				Optional:    true,
+			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"planned_request": schema.StringAttribute{
+				Description: "Request apply sends to the device, as JSON with method, href and body. Unknown while the configuration has unknown values.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -113,6 +129,15 @@ func (r *OTUDiagResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.client = req.ProviderData.(*xrcm_pf.Client)
 }
 
+// ModifyPlan sets planned_request to the request apply will send and fails
+// traffic affecting changes on modules with active LCs and invalid
+// auto_revert_after.
+func (r *OTUDiagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanRequest(ctx, req, resp, true, r.plannedRequest)
+	checkTrafficImpact(ctx, r.client, req, resp, otuDiagTrafficAttributes)
+	validateAutoRevertAfter(ctx, req, resp)
+}
+
 func (r OTUDiagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data OTUDiagResourceData
 
@@ -125,7 +150,12 @@ func (r OTUDiagResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	//r.update(&data, ctx, &resp.Diagnostics)
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, true))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -163,7 +193,12 @@ func (r OTUDiagResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	data.PlannedRequest = appliedRequestValue(r.plannedRequest(&data, ctx, false))
 	r.update(&data, ctx, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() {
+		uri, cmd := r.updateRequest(&data)
+		awaitDiagnosticRevert(ctx, r.client, data.N.ValueString(), uri, cmd, data.AutoRevertAfter, &resp.Diagnostics)
+	}
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
@@ -179,6 +214,13 @@ func (r OTUDiagResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	// Restore the non-loopback, non-PRBS state.
+	uri, cmd := r.updateRequest(&data)
+	revertDiagnostic(ctx, r.client, data.N.ValueString(), uri, cmd, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.State.RemoveResource(ctx)
 }
 
@@ -197,23 +239,7 @@ func (r *OTUDiagResource) update(plan *OTUDiagResourceData, ctx context.Context,
 		return
 	}
 
-	var cmd = make(map[string]interface{})
-
-	if !(plan.TermLB.IsNull()) {
-		cmd["termLB"] = plan.TermLB.ValueString()
-	}
-
-	if !(plan.TermLBDuration.IsNull()) {
-		cmd["termLBDuration"] = plan.TermLBDuration.ValueInt64()
-	}
-
-	if !(plan.FacLB.IsNull()) {
-		cmd["facLB"] = plan.FacLB.ValueString()
-	}
-
-	if !(plan.FacLBDuration.IsNull()) {
-		cmd["facLBDuration"] = plan.FacLBDuration.ValueInt64()
-	}
+	uri, cmd := r.updateRequest(plan)
 
 	if len(cmd) == 0. {
 		return
@@ -228,14 +254,9 @@ func (r *OTUDiagResource) update(plan *OTUDiagResourceData, ctx context.Context,
 		return
 	}
 
-	href := after(plan.Id.ValueString(), "/")
-	if len(href) == 0 {
-		href = "/otus/" + plan.OtuId.ValueString() + "/diagnostic"
-	}
-
-	tflog.Debug(ctx, "OTUDiagResource: updated ## ", map[string]interface{}{"device": plan.N.ValueString(), "URL": "resources" + href})
+	tflog.Debug(ctx, "OTUDiagResource: updated ## ", map[string]interface{}{"device": plan.N.ValueString(), "URL": uri})
 
-	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", "resources"+href, rb)
+	body, deviceId, err := r.client.ExecuteDeviceHttpCommand(plan.N.ValueString(), "PUT", uri, rb)
 
 	if err != nil {
 		diags.AddError(
@@ -262,6 +283,40 @@ func (r *OTUDiagResource) update(plan *OTUDiagResourceData, ctx context.Context,
 
 }
 
+// updateRequest returns the device command uri and body update PUTs for the plan.
+func (r *OTUDiagResource) updateRequest(plan *OTUDiagResourceData) (string, map[string]interface{}) {
+	var cmd = make(map[string]interface{})
+
+	if !(plan.TermLB.IsNull()) {
+		cmd["termLB"] = plan.TermLB.ValueString()
+	}
+
+	if !(plan.TermLBDuration.IsNull()) {
+		cmd["termLBDuration"] = plan.TermLBDuration.ValueInt64()
+	}
+
+	if !(plan.FacLB.IsNull()) {
+		cmd["facLB"] = plan.FacLB.ValueString()
+	}
+
+	if !(plan.FacLBDuration.IsNull()) {
+		cmd["facLBDuration"] = plan.FacLBDuration.ValueInt64()
+	}
+
+	href := after(plan.Id.ValueString(), "/")
+	if len(href) == 0 {
+		href = "/otus/" + plan.OtuId.ValueString() + "/diagnostic"
+	}
+
+	return "resources" + href, cmd
+}
+
+// plannedRequest returns the planned_request of the plan.
+func (r *OTUDiagResource) plannedRequest(plan *OTUDiagResourceData, ctx context.Context, create bool) types.String {
+	uri, cmd := r.updateRequest(plan)
+	return plannedRequestValue("PUT", uri, cmd)
+}
+
 func (r *OTUDiagResource) read(state *OTUDiagResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
 	if state.OtuId.IsNull() {
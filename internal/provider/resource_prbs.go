@@ -0,0 +1,454 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &PRBSTestResource{}
+	_ resource.ResourceWithConfigure  = &PRBSTestResource{}
+	_ resource.ResourceWithModifyPlan = &PRBSTestResource{}
+)
+
+// NewPRBSTestResource is a helper function to simplify the provider implementation.
+func NewPRBSTestResource() resource.Resource {
+	return &PRBSTestResource{}
+}
+
+// PRBSTestResource runs a PRBS test when it is created: the generator sends
+// a PRBS for soak_time to the monitor, and the results are kept in the state.
+type PRBSTestResource struct {
+	client *xrcm_pf.Client
+}
+
+// PRBSEndpointData is a DSC, with lineptpid, carrierid and dscid, or an
+// Ethernet, with ethernetid.
+type PRBSEndpointData struct {
+	N          types.String `tfsdk:"n"`
+	LinePTPId  types.String `tfsdk:"lineptpid"`
+	CarrierId  types.String `tfsdk:"carrierid"`
+	DscId      types.String `tfsdk:"dscid"`
+	EthernetId types.String `tfsdk:"ethernetid"`
+}
+
+type PRBSTestResourceData struct {
+	Id                 types.String     `tfsdk:"id"`
+	Generator          PRBSEndpointData `tfsdk:"generator"`
+	Monitor            PRBSEndpointData `tfsdk:"monitor"`
+	SoakTime           types.String     `tfsdk:"soak_time"`
+	MaxBer             types.Float64    `tfsdk:"max_ber"`
+	AllowTrafficImpact types.Bool       `tfsdk:"allow_traffic_impact"`
+	SyncStatus         types.String     `tfsdk:"sync_status"`
+	ErrorCount         types.Int64      `tfsdk:"error_count"`
+	Ber                types.Float64    `tfsdk:"ber"`
+	Passed             types.Bool       `tfsdk:"passed"`
+}
+
+// Metadata returns the resource type name.
+func (r *PRBSTestResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_prbs_test"
+}
+
+// prbsEndpointAttribute is the schema of a PRBS test endpoint.
+func prbsEndpointAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: description,
+		Required:    true,
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"n": schema.StringAttribute{
+				Description: "XR Device Name",
+				Required:    true,
+			},
+			"lineptpid": schema.StringAttribute{
+				Description: "line ptp id of the DSC",
+				Optional:    true,
+			},
+			"carrierid": schema.StringAttribute{
+				Description: "carrier id of the DSC",
+				Optional:    true,
+			},
+			"dscid": schema.StringAttribute{
+				Description: "DSC id",
+				Optional:    true,
+			},
+			"ethernetid": schema.StringAttribute{
+				Description: "Ethernet id, instead of a DSC",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Schema defines the schema for the resource.
+func (r *PRBSTestResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a PRBS test between a DSC or Ethernet generator and monitor, and captures its results",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Monitor device name followed by the href of its diagnostic.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"generator": prbsEndpointAttribute("Endpoint generating the PRBS, facPRBSGen"),
+			"monitor":   prbsEndpointAttribute("Endpoint monitoring the PRBS, facPRBSMon"),
+			"soak_time": schema.StringAttribute{
+				Description: "How long the PRBS runs before the results are read, e.g. 60s",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"max_ber": schema.Float64Attribute{
+				Description: "Highest bit error rate the test passes with, default 0, no error at all",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.RequiresReplace(),
+				},
+			},
+			"allow_traffic_impact": schema.BoolAttribute{
+				Description: "Allow changes that take down the traffic of the module's active LCs, default false.",
+				Optional:    true,
+			},
+			"sync_status": schema.StringAttribute{
+				Description: "PRBS sync status of the monitor at the end of the soak time",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"error_count": schema.Int64Attribute{
+				Description: "PRBS bit errors counted by the monitor",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ber": schema.Float64Attribute{
+				Description: "PRBS bit error rate the monitor reports, null if it does not",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"passed": schema.BoolAttribute{
+				Description: "The monitor is in sync and the bit error rate is at most max_ber",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *PRBSTestResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*xrcm_pf.Client)
+}
+
+// ModifyPlan fails the plan of an invalid soak_time or endpoint, and of a
+// test whose generator takes down the traffic of a module with active LCs.
+func (r *PRBSTestResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan PRBSTestResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.SoakTime.IsUnknown() {
+		if _, err := time.ParseDuration(plan.SoakTime.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("soak_time"),
+				"Invalid Soak Time",
+				"soak_time must be a duration, e.g. 60s: "+err.Error(),
+			)
+		}
+	}
+	for name, endpoint := range map[string]PRBSEndpointData{"generator": plan.Generator, "monitor": plan.Monitor} {
+		if _, ok := prbsEndpointHref(endpoint); !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid PRBS Endpoint",
+				"Set either lineptpid, carrierid and dscid of a DSC, or ethernetid of an Ethernet.",
+			)
+		}
+	}
+
+	// The test only runs on create.
+	if !req.State.Raw.IsNull() || r.client == nil || r.client.AllowTrafficImpact || plan.AllowTrafficImpact.ValueBool() {
+		return
+	}
+	// The generator and the monitor both take down the traffic of their module.
+	checked := make(map[string]bool)
+	for _, endpoint := range []struct {
+		name string
+		n    types.String
+	}{{"generator", plan.Generator.N}, {"monitor", plan.Monitor.N}} {
+		n := endpoint.n.ValueString()
+		if endpoint.n.IsUnknown() || checked[n] {
+			continue
+		}
+		checked[n] = true
+		lcs, err := activeLCs(ctx, r.client, n)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(endpoint.name),
+				"PRBSTestResource: Unknown Traffic Impact",
+				"Could not GET the LCs of device "+n+" to check the traffic impact of the PRBS "+endpoint.name+", unexpected error: "+err.Error()+". "+
+					"Set allow_traffic_impact = true on the resource to apply it anyway.",
+			)
+			continue
+		}
+		if lcs > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(endpoint.name),
+				"Traffic Affecting Change",
+				"The PRBS "+endpoint.name+" on device "+n+" takes down the traffic of its "+strconv.Itoa(lcs)+" active LCs. "+
+					"Set allow_traffic_impact = true on the resource, or allow_traffic_impact in the provider configuration, to apply it.",
+			)
+		}
+	}
+}
+
+func (r PRBSTestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PRBSTestResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+
+	tflog.Debug(ctx, "PRBSTestResource: Create", map[string]interface{}{"PRBSTestResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.run(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read keeps the results of the test, it is not run again.
+func (r PRBSTestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PRBSTestResourceData
+
+	diags := req.State.Get(ctx, &data)
+	tflog.Debug(ctx, "PRBSTestResource: Read", map[string]interface{}{"PRBSTestResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only changes allow_traffic_impact, every other change replaces the
+// resource and runs the test again.
+func (r PRBSTestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PRBSTestResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	tflog.Debug(ctx, "PRBSTestResource: Update", map[string]interface{}{"PRBSTestResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete only removes the results, the test turned the PRBS off already.
+func (r PRBSTestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(ctx, "PRBSTestResource: Delete")
+
+	resp.State.RemoveResource(ctx)
+}
+
+// run turns the monitor then the generator on, waits soak_time, reads the
+// results from the monitor and turns both off, even if the test failed.
+func (r *PRBSTestResource) run(plan *PRBSTestResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	soakTime, err := time.ParseDuration(plan.SoakTime.ValueString())
+	if err != nil {
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: Could not parse soak_time, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	genHref, _ := prbsEndpointHref(plan.Generator)
+	monHref, _ := prbsEndpointHref(plan.Monitor)
+	genN, monN := plan.Generator.N.ValueString(), plan.Monitor.N.ValueString()
+	genUri, monUri := "resources"+genHref, "resources"+monHref
+
+	monCmd := map[string]interface{}{"facPRBSMon": true}
+	genCmd := map[string]interface{}{"facPRBSGen": true}
+
+	// Both sides are turned off whatever happens once one is turned on.
+	defer func() {
+		revertDiagnostic(ctx, r.client, genN, genUri, genCmd, diags)
+		revertDiagnostic(ctx, r.client, monN, monUri, monCmd, diags)
+	}()
+
+	deviceId := r.setDiagnostic(ctx, monN, monUri, monCmd, diags)
+	if diags.HasError() {
+		return
+	}
+	r.setDiagnostic(ctx, genN, genUri, genCmd, diags)
+	if diags.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "PRBSTestResource: run ## soaking", map[string]interface{}{"generator": genN + genHref, "monitor": monN + monHref, "soak_time": soakTime.String()})
+
+	timer := time.NewTimer(soakTime)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: Cancelled before the end of soak_time: "+ctx.Err().Error(),
+		)
+		return
+	case <-timer.C:
+	}
+
+	body, _, err := r.client.ExecuteDeviceHttpCommandNoCache(monN, "GET", monUri, nil)
+	if err != nil {
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: Could not Get PRBS results of "+monUri+" on device "+monN+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	var results struct {
+		Data struct {
+			Content xrcm.Diagnostic `json:"content"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: Could not Get PRBS results of "+monUri+" on device "+monN+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "PRBSTestResource: run ## results", map[string]interface{}{"Device": monN, "URL": monUri, "response": string(body)})
+
+	plan.Id = types.StringValue(monN + monHref)
+	setPRBSResults(plan, &results.Data.Content, diags)
+
+	tflog.Debug(ctx, "PRBSTestResource: run ## ", map[string]interface{}{"Device": deviceId, "plan": plan})
+}
+
+// setDiagnostic PUTs cmd to the diagnostic at uri and returns the device id.
+func (r *PRBSTestResource) setDiagnostic(ctx context.Context, deviceName string, uri string, cmd map[string]interface{}, diags *diag.Diagnostics) string {
+	rb, err := json.Marshal(cmd)
+	if err != nil {
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: Could not Set Diagnostic, unexpected error: "+err.Error(),
+		)
+		return ""
+	}
+
+	tflog.Debug(ctx, "PRBSTestResource: run ## ", map[string]interface{}{"Device": deviceName, "URL": uri, "Input data": string(rb)})
+
+	_, deviceId, err := r.client.ExecuteDeviceHttpCommand(deviceName, "PUT", uri, rb)
+	if err != nil {
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: Could not Set Diagnostic "+uri+" on device "+deviceName+", unexpected error: "+err.Error(),
+		)
+	}
+	return deviceId
+}
+
+// prbsEndpointHref returns the href of the diagnostic of an endpoint, false if
+// it is neither a DSC nor an Ethernet.
+func prbsEndpointHref(e PRBSEndpointData) (string, bool) {
+	dsc := !e.LinePTPId.IsNull() && !e.CarrierId.IsNull() && !e.DscId.IsNull()
+	ethernet := !e.EthernetId.IsNull()
+	switch {
+	case dsc && !ethernet:
+		return "/lineptps/" + e.LinePTPId.ValueString() + "/carriers/" + e.CarrierId.ValueString() + "/dscs/" + e.DscId.ValueString() + "/diagnostic", true
+	case ethernet && e.LinePTPId.IsNull() && e.CarrierId.IsNull() && e.DscId.IsNull():
+		return "/ethernets/" + e.EthernetId.ValueString() + "/diagnostic", true
+	}
+	return "", false
+}
+
+// setPRBSResults sets the results of the test from the monitor diagnostic.
+// The test passes when the monitor is in sync and has no errors, or a bit
+// error rate of at most max_ber.
+func setPRBSResults(plan *PRBSTestResourceData, d *xrcm.Diagnostic, diags *diag.Diagnostics) {
+	plan.SyncStatus = stringPointerValue(d.FacPRBSSyncStatus)
+	plan.ErrorCount = int64PointerValue(d.FacPRBSErrorCount)
+	plan.Ber = types.Float64Null()
+	if d.FacPRBSBer != nil {
+		plan.Ber = types.Float64Value(*d.FacPRBSBer)
+	}
+
+	var missing []string
+	if d.FacPRBSSyncStatus == nil {
+		missing = append(missing, "facPRBSSyncStatus")
+	}
+	if d.FacPRBSErrorCount == nil {
+		missing = append(missing, "facPRBSErrorCount")
+	}
+	if len(missing) > 0 {
+		diags.AddError(
+			"PRBSTestResource: run ##: Error Run PRBS Test",
+			"Run: The monitor did not report "+strings.Join(missing, ", ")+", the PRBS results are unknown.",
+		)
+		plan.Passed = types.BoolValue(false)
+		return
+	}
+
+	passed := strings.EqualFold(*d.FacPRBSSyncStatus, "inSync")
+	if passed && *d.FacPRBSErrorCount > 0 {
+		passed = d.FacPRBSBer != nil && *d.FacPRBSBer <= plan.MaxBer.ValueFloat64()
+	}
+	plan.Passed = types.BoolValue(passed)
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"testing"
+
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPRBSEndpointHref(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint PRBSEndpointData
+		want     string
+		ok       bool
+	}{
+		{
+			"dsc",
+			PRBSEndpointData{LinePTPId: types.StringValue("1"), CarrierId: types.StringValue("1"), DscId: types.StringValue("3"), EthernetId: types.StringNull()},
+			"/lineptps/1/carriers/1/dscs/3/diagnostic", true,
+		},
+		{
+			"ethernet",
+			PRBSEndpointData{LinePTPId: types.StringNull(), CarrierId: types.StringNull(), DscId: types.StringNull(), EthernetId: types.StringValue("2")},
+			"/ethernets/2/diagnostic", true,
+		},
+		{
+			"dsc without carrier",
+			PRBSEndpointData{LinePTPId: types.StringValue("1"), CarrierId: types.StringNull(), DscId: types.StringValue("3"), EthernetId: types.StringNull()},
+			"", false,
+		},
+		{
+			"dsc and ethernet",
+			PRBSEndpointData{LinePTPId: types.StringValue("1"), CarrierId: types.StringValue("1"), DscId: types.StringValue("3"), EthernetId: types.StringValue("2")},
+			"", false,
+		},
+		{
+			"ethernet with lineptp",
+			PRBSEndpointData{LinePTPId: types.StringValue("1"), CarrierId: types.StringNull(), DscId: types.StringNull(), EthernetId: types.StringValue("2")},
+			"", false,
+		},
+		{
+			"nothing",
+			PRBSEndpointData{LinePTPId: types.StringNull(), CarrierId: types.StringNull(), DscId: types.StringNull(), EthernetId: types.StringNull()},
+			"", false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := prbsEndpointHref(tt.endpoint)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("prbsEndpointHref() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSetPRBSResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBer     types.Float64
+		diagnostic xrcm.Diagnostic
+		passed     bool
+		errorCount types.Int64
+		ber        types.Float64
+		hasError   bool
+	}{
+		{
+			name:       "no errors",
+			maxBer:     types.Float64Null(),
+			diagnostic: xrcm.Diagnostic{FacPRBSSyncStatus: xrcm.String("inSync"), FacPRBSErrorCount: xrcm.Int64(0)},
+			passed:     true,
+			errorCount: types.Int64Value(0),
+			ber:        types.Float64Null(),
+		},
+		{
+			name:       "errors above max_ber",
+			maxBer:     types.Float64Value(1e-12),
+			diagnostic: xrcm.Diagnostic{FacPRBSSyncStatus: xrcm.String("inSync"), FacPRBSErrorCount: xrcm.Int64(5), FacPRBSBer: xrcm.Float64(1e-9)},
+			passed:     false,
+			errorCount: types.Int64Value(5),
+			ber:        types.Float64Value(1e-9),
+		},
+		{
+			name:       "errors within max_ber",
+			maxBer:     types.Float64Value(1e-6),
+			diagnostic: xrcm.Diagnostic{FacPRBSSyncStatus: xrcm.String("inSync"), FacPRBSErrorCount: xrcm.Int64(5), FacPRBSBer: xrcm.Float64(1e-9)},
+			passed:     true,
+			errorCount: types.Int64Value(5),
+			ber:        types.Float64Value(1e-9),
+		},
+		{
+			name:       "errors without ber",
+			maxBer:     types.Float64Value(1e-6),
+			diagnostic: xrcm.Diagnostic{FacPRBSSyncStatus: xrcm.String("inSync"), FacPRBSErrorCount: xrcm.Int64(5)},
+			passed:     false,
+			errorCount: types.Int64Value(5),
+			ber:        types.Float64Null(),
+		},
+		{
+			name:       "not in sync",
+			maxBer:     types.Float64Null(),
+			diagnostic: xrcm.Diagnostic{FacPRBSSyncStatus: xrcm.String("notInSync"), FacPRBSErrorCount: xrcm.Int64(0)},
+			passed:     false,
+			errorCount: types.Int64Value(0),
+			ber:        types.Float64Null(),
+		},
+		{
+			name:       "missing sync status",
+			maxBer:     types.Float64Null(),
+			diagnostic: xrcm.Diagnostic{FacPRBSErrorCount: xrcm.Int64(0)},
+			passed:     false,
+			errorCount: types.Int64Value(0),
+			ber:        types.Float64Null(),
+			hasError:   true,
+		},
+		{
+			name:       "missing error count",
+			maxBer:     types.Float64Null(),
+			diagnostic: xrcm.Diagnostic{FacPRBSSyncStatus: xrcm.String("inSync")},
+			passed:     false,
+			errorCount: types.Int64Null(),
+			ber:        types.Float64Null(),
+			hasError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PRBSTestResourceData{MaxBer: tt.maxBer}
+			var diags diag.Diagnostics
+			setPRBSResults(&plan, &tt.diagnostic, &diags)
+			if diags.HasError() != tt.hasError {
+				t.Errorf("setPRBSResults() errors = %v, want error %v", diags, tt.hasError)
+			}
+			if plan.Passed != types.BoolValue(tt.passed) {
+				t.Errorf("Passed = %v, want %v", plan.Passed, tt.passed)
+			}
+			if plan.ErrorCount != tt.errorCount {
+				t.Errorf("ErrorCount = %v, want %v", plan.ErrorCount, tt.errorCount)
+			}
+			if plan.Ber != tt.ber {
+				t.Errorf("Ber = %v, want %v", plan.Ber, tt.ber)
+			}
+		})
+	}
+}
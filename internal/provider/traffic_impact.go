@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// trafficAttribute is an attribute whose change takes down the live traffic
+// of the module, key being its field on the device. affects reports whether a
+// planned value does, nil meaning that any change does.
+type trafficAttribute struct {
+	name    string
+	key     string
+	affects func(v attr.Value) bool
+}
+
+var (
+	carrierTrafficAttributes = []trafficAttribute{
+		{name: "constellationfrequency", key: "constellationFrequency"},
+		{name: "modulation", key: "modulation"},
+		{name: "baudrate", key: "baudRate"},
+		{name: "maxdscs", key: "maxDSCs"},
+	}
+	cfgTrafficAttributes = []trafficAttribute{
+		{name: "trafficmode", key: "trafficMode"},
+		{name: "configuredrole", key: "configuredRole"},
+	}
+	acDiagTrafficAttributes = []trafficAttribute{
+		{name: "termlb", key: "termLB", affects: loopbackEnabled},
+	}
+	carrierDiagTrafficAttributes = []trafficAttribute{
+		{name: "termlb", key: "termLB", affects: loopbackEnabled},
+	}
+	// A PRBS monitor only checks the received signal, the generator replaces
+	// the traffic.
+	dscDiagTrafficAttributes = []trafficAttribute{
+		{name: "facprbsgen", key: "facPRBSGen", affects: prbsEnabled},
+	}
+	ethernetDiagTrafficAttributes = []trafficAttribute{
+		{name: "termlb", key: "termLB", affects: loopbackEnabled},
+		{name: "faclb", key: "facLB", affects: loopbackEnabled},
+		{name: "facprbsgen", key: "facPRBSGen", affects: prbsEnabled},
+		{name: "termprbsgen", key: "termPRBSGen", affects: prbsEnabled},
+	}
+	otuDiagTrafficAttributes = []trafficAttribute{
+		{name: "termlb", key: "termLB", affects: loopbackEnabled},
+		{name: "faclb", key: "facLB", affects: loopbackEnabled},
+	}
+)
+
+// loopbackEnabled reports whether a loopback value is anything but disabled.
+func loopbackEnabled(v attr.Value) bool {
+	s, ok := v.(types.String)
+	if !ok || s.IsNull() {
+		return false
+	}
+	return s.IsUnknown() || (len(s.ValueString()) > 0 && !strings.EqualFold(s.ValueString(), "disabled"))
+}
+
+// prbsEnabled reports whether a PRBS value turns it on.
+func prbsEnabled(v attr.Value) bool {
+	b, ok := v.(types.Bool)
+	if !ok {
+		return false
+	}
+	return b.IsUnknown() || b.ValueBool()
+}
+
+// checkTrafficImpact fails the plan of a create or update that changes one of
+// the attributes on a module with active LCs, unless the resource sets
+// allow_traffic_impact or the provider allows traffic impact. A create, having
+// no state, changes the attributes its planned_request sets to other values
+// than the device has.
+func checkTrafficImpact(ctx context.Context, client *xrcm_pf.Client, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attributes []trafficAttribute) {
+	if req.Plan.Raw.IsNull() || client == nil || client.AllowTrafficImpact {
+		return
+	}
+
+	var allow types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("allow_traffic_impact"), &allow)...)
+	if resp.Diagnostics.HasError() || allow.ValueBool() {
+		return
+	}
+
+	var n types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("n"), &n)...)
+	if resp.Diagnostics.HasError() || n.IsUnknown() {
+		return
+	}
+
+	create := req.State.Raw.IsNull()
+	var request *plannedRequest
+	var content map[string]interface{}
+	if create {
+		request, content = plannedRequestContent(ctx, client, n.ValueString(), resp)
+	}
+
+	var changed []string
+	for _, a := range attributes {
+		var planned, current attr.Value
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root(a.name), &planned)...)
+		if !create {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(a.name), &current)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if planned.IsNull() || (!create && planned.Equal(current)) {
+			continue
+		}
+		if request != nil {
+			v, set := request.Body[a.key]
+			if !set || (content != nil && jsonValueEqual(v, content[a.key])) {
+				continue
+			}
+		}
+		if a.affects == nil || a.affects(planned) {
+			changed = append(changed, a.name)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	lcs, err := activeLCs(ctx, client, n.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"checkTrafficImpact: Unknown Traffic Impact",
+			"Could not GET the LCs of device "+n.ValueString()+" to check the traffic impact of "+strings.Join(changed, ", ")+", unexpected error: "+err.Error()+". "+
+				"Set allow_traffic_impact = true on the resource, or allow_traffic_impact in the provider configuration, to apply it anyway.",
+		)
+		return
+	}
+	tflog.Debug(ctx, "checkTrafficImpact: ", map[string]interface{}{"Device": n.ValueString(), "changed": changed, "lcs": lcs})
+	if lcs == 0 {
+		return
+	}
+
+	for _, name := range changed {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(name),
+			"Traffic Affecting Change",
+			"Changing "+name+" on device "+n.ValueString()+" takes down the traffic of its "+strconv.Itoa(lcs)+" active LCs. "+
+				"Set allow_traffic_impact = true on the resource, or allow_traffic_impact in the provider configuration, to apply it.",
+		)
+	}
+}
+
+// plannedRequestContent returns the planned_request of a create and the
+// current content of the resource it updates. Either is nil when unknown, the
+// content when the resource cannot be read.
+func plannedRequestContent(ctx context.Context, client *xrcm_pf.Client, deviceName string, resp *resource.ModifyPlanResponse) (*plannedRequest, map[string]interface{}) {
+	var planned types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("planned_request"), &planned)...)
+	if resp.Diagnostics.HasError() || planned.IsNull() || planned.IsUnknown() {
+		return nil, nil
+	}
+	var request plannedRequest
+	if err := json.Unmarshal([]byte(planned.ValueString()), &request); err != nil {
+		return nil, nil
+	}
+
+	body, _, err := client.ExecuteDeviceHttpCommand(deviceName, "GET", request.Href, nil)
+	if err != nil {
+		tflog.Debug(ctx, "checkTrafficImpact: could not GET "+request.Href, map[string]interface{}{"error": err.Error()})
+		return &request, nil
+	}
+	content, err := getContent(body)
+	if err != nil {
+		return &request, nil
+	}
+	return &request, content
+}
+
+// jsonValueEqual reports whether a planned request value is the value the
+// device returned, comparing their JSON.
+func jsonValueEqual(planned interface{}, current interface{}) bool {
+	p, err := json.Marshal(planned)
+	if err != nil {
+		return false
+	}
+	c, err := json.Marshal(current)
+	if err != nil {
+		return false
+	}
+	return string(p) == string(c)
+}
+
+// lcCarriesTraffic reports whether an LC is set up on the module: its
+// configState is completed, or the device does not report it.
+func lcCarriesTraffic(lc *xrcm.LC) bool {
+	return lc.ConfigState == nil || strings.EqualFold(*lc.ConfigState, "completed")
+}
+
+// activeLCs returns the number of LCs carrying traffic on the device.
+func activeLCs(ctx context.Context, client *xrcm_pf.Client, deviceName string) (int, error) {
+	c := xrcm.NewClientWithRequester(client)
+	hrefs, err := c.ListLCs(ctx, deviceName)
+	if err != nil {
+		return 0, err
+	}
+	active := 0
+	for _, href := range hrefs {
+		lc, err := c.GetLC(ctx, deviceName, href)
+		if err != nil {
+			return 0, err
+		}
+		if lcCarriesTraffic(lc) {
+			active++
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"testing"
+
+	xrcm "github.com/infinera/terraform-provider-xr/pkg/xrcm/v1"
+)
+
+func TestLCCarriesTraffic(t *testing.T) {
+	tests := []struct {
+		state *string
+		want  bool
+	}{
+		{nil, true},
+		{xrcm.String("completed"), true},
+		{xrcm.String("Completed"), true},
+		{xrcm.String("pending"), false},
+		{xrcm.String("failed"), false},
+	}
+	for _, tt := range tests {
+		if got := lcCarriesTraffic(&xrcm.LC{ConfigState: tt.state}); got != tt.want {
+			t.Errorf("lcCarriesTraffic(%v) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestJSONValueEqual(t *testing.T) {
+	tests := []struct {
+		name    string
+		planned interface{}
+		current interface{}
+		want    bool
+	}{
+		{"same int64 and float64", int64(193100000), float64(193100000), true},
+		{"other number", int64(193100000), float64(193150000), false},
+		{"same string", "16QAM", "16QAM", true},
+		{"other string", "16QAM", "QPSK", false},
+		{"same bool", true, true, true},
+		{"missing on device", "16QAM", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonValueEqual(tt.planned, tt.current); got != tt.want {
+				t.Errorf("jsonValueEqual(%v, %v) = %v, want %v", tt.planned, tt.current, got, tt.want)
+			}
+		})
+	}
+}
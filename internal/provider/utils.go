@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+	"sync"
 
-	"terraform-provider-xrcm/internal/xrcm_pf"
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
 
 	"github.com/fujiwara/tfstate-lookup/tfstate"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -79,6 +83,84 @@ func SetResourceId(deviceName string, Id *types.String, body []byte) (c map[stri
 	return content, nil
 }
 
+// getResponseHref returns the href of the resource carried in a device
+// response body, or an empty string if the body has none.
+func getResponseHref(body []byte) string {
+	resourceId, content, _ := getResourceIdNContent(body)
+	if href, ok := content["href"].(string); ok && len(href) > 0 {
+		return href
+	}
+	if href, ok := resourceId["href"].(string); ok && len(href) > 0 {
+		return href
+	}
+	return ""
+}
+
+// createdHref returns the href of the resource-link a create POSTed to uri,
+// from the response body or else from uri and the id of the resource, if any.
+func createdHref(body []byte, uri string, id string) string {
+	if href := getResponseHref(body); len(href) > 0 {
+		return href
+	}
+	if len(id) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(uri, "resource-links") + "/" + id
+}
+
+// rollbackCreate deletes the resource-links created by a create that failed
+// in a later step, newest first, so the next apply does not collide with them.
+// Any href that cannot be deleted is reported as orphaned.
+func rollbackCreate(ctx context.Context, client *xrcm_pf.Client, deviceName string, diags *diag.Diagnostics, hrefs ...string) {
+	var orphaned []string
+	for i := len(hrefs) - 1; i >= 0; i-- {
+		href := hrefs[i]
+		if len(href) == 0 {
+			orphaned = append(orphaned, "unknown href: the create response did not identify the resource")
+			continue
+		}
+		tflog.Debug(ctx, "rollbackCreate: delete ## ", map[string]interface{}{"Device": deviceName, "href": href})
+
+		_, _, err := client.ExecuteDeviceHttpCommand(deviceName, "DELETE", "resource-links"+href, nil)
+		if err != nil && !strings.Contains(err.Error(), "status: 404") {
+			orphaned = append(orphaned, href+": "+err.Error())
+		}
+	}
+
+	if len(orphaned) > 0 {
+		diags.AddError(
+			"Error Rollback Create",
+			"Rollback: Could not delete the resource-links created on device "+deviceName+", remove them before the next apply:\n"+strings.Join(orphaned, "\n"),
+		)
+	}
+}
+
+// rollbackOnError rolls back a create whose steps after the POST added an
+// error, clearing the id of the plan. Deferred right after the POST, so that
+// any later step failing does not leave the resource-link on the device.
+func rollbackOnError(ctx context.Context, client *xrcm_pf.Client, deviceName string, diags *diag.Diagnostics, id *types.String, href func() string) {
+	if !diags.HasError() {
+		return
+	}
+	*id = types.StringNull()
+	rollbackCreate(ctx, client, deviceName, diags, href())
+}
+
+// resourceExists reports whether the resource at href is already present on
+// the device.
+func resourceExists(ctx context.Context, client *xrcm_pf.Client, deviceName string, href string) (bool, error) {
+	tflog.Debug(ctx, "resourceExists: ", map[string]interface{}{"Device": deviceName, "href": href})
+
+	_, _, err := client.ExecuteDeviceHttpCommand(deviceName, "GET", "resources"+href, nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "status: 404") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func after(value string, a string) string {
 	// Get substring after a string.
 	pos := strings.Index(value, a)
@@ -90,18 +172,35 @@ func after(value string, a string) string {
 
 var mytfstate *tfstate.TFState = nil
 var tfstatefile string = ""
-
-func GetTFState(ctx context.Context, file string) (*tfstate.TFState, error) {
-	if mytfstate == nil || tfstatefile != file {
-		tfstatefile = file
-		mytfstate, _ = tfstate.ReadFile(ctx, file)
+var tfstateMutex sync.Mutex
+
+// GetTFState returns the Terraform state at location, a file path or the URL
+// of a backend: s3://bucket/key, gs://bucket/key,
+// azurerm://resource_group/account/container/key,
+// remote://app.terraform.io/organization/workspace, http(s):// or file://.
+// The last state read is kept.
+func GetTFState(ctx context.Context, location string) (*tfstate.TFState, error) {
+	tfstateMutex.Lock()
+	defer tfstateMutex.Unlock()
+	if mytfstate == nil || tfstatefile != location {
+		state, err := tfstate.ReadURL(ctx, location)
+		if err != nil {
+			return nil, err
+		}
+		tfstatefile = location
+		mytfstate = state
 	}
 	return mytfstate, nil
 }
 
 func LookupTFState(key string) (interface{}, error) {
+	tfstateMutex.Lock()
+	defer tfstateMutex.Unlock()
 	if mytfstate != nil {
-		value, _ := mytfstate.Lookup(key)
+		value, err := mytfstate.Lookup(key)
+		if err != nil {
+			return nil, err
+		}
 		if value != nil {
 			return value.Value, nil
 		}
@@ -109,15 +208,16 @@ func LookupTFState(key string) (interface{}, error) {
 	return nil, nil
 }
 
-func GetAndLookupTFState(ctx context.Context, file string, key string) (interface{}, error) {
-	state, _ := GetTFState(ctx, file)
-	if state != nil {
-		value, _ := state.Lookup(key)
-		if value != nil {
-			return value.Value, nil
-		}
+func GetAndLookupTFState(ctx context.Context, location string, key string) (interface{}, error) {
+	state, err := GetTFState(ctx, location)
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	value, err := state.Lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return value.Value, nil
 }
 
 func GetResource(ctx context.Context, client *xrcm_pf.Client, deviceName string, query string) (map[string]interface{}, string, error) {
@@ -161,6 +261,159 @@ func GetResourcebyID(ctx context.Context, client *xrcm_pf.Client, deviceId strin
 	return data, nil
 }
 
+// Status of a module entry in the multi-module data sources.
+const (
+	moduleStatusOK    = "OK"
+	moduleStatusError = "ERROR"
+)
+
+// reportModuleError records a module that could not be read. With
+// skipUnavailable it sets the module's status and error and the read goes on
+// with the other modules; otherwise it adds an error diagnostic.
+func reportModuleError(skipUnavailable bool, diags *diag.Diagnostics, status *types.String, errorMsg *types.String, summary string, detail string) {
+	if !skipUnavailable {
+		diags.AddError(summary, detail)
+		return
+	}
+	*status = types.StringValue(moduleStatusError)
+	*errorMsg = types.StringValue(detail)
+}
+
+// stringPointerValue returns a field of the typed client, null when the
+// device did not return it.
+func stringPointerValue(v *string) types.String {
+	if v == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(*v)
+}
+
+func int64PointerValue(v *int64) types.Int64 {
+	if v == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(*v)
+}
+
+func boolPointerValue(v *bool) types.Bool {
+	if v == nil {
+		return types.BoolNull()
+	}
+	return types.BoolValue(*v)
+}
+
+// bitsPointerValue returns the bits set in a bitmask field of the typed
+// client, see getBits.
+func bitsPointerValue(v *int64) types.List {
+	if v == nil {
+		return types.ListNull(types.Int64Type)
+	}
+	l, _ := types.ListValue(types.Int64Type, getBits(int(*v)))
+	return l
+}
+
+// capabilitiesValue returns the capabilities of a resource as a map of
+// strings, values other than strings as their JSON text.
+func capabilitiesValue(capabilities map[string]interface{}) types.Map {
+	capMap := make(map[string]attr.Value)
+	for k, v := range capabilities {
+		if s, ok := v.(string); ok {
+			capMap[k] = types.StringValue(s)
+			continue
+		}
+		rb, _ := json.Marshal(v)
+		capMap[k] = types.StringValue(string(rb))
+	}
+	m, _ := types.MapValue(types.StringType, capMap)
+	return m
+}
+
+// plannedRequest is the request apply sends to the device, shown during plan
+// as the planned_request attribute.
+type plannedRequest struct {
+	Method string                 `json:"method"`
+	Href   string                 `json:"href"`
+	Body   map[string]interface{} `json:"body,omitempty"`
+}
+
+// plannedRequestValue returns the planned_request JSON of a request to the
+// device command uri. A request without a body is not sent, which is null.
+func plannedRequestValue(method string, uri string, body map[string]interface{}) types.String {
+	if len(body) == 0 {
+		return types.StringNull()
+	}
+	rb, err := json.Marshal(plannedRequest{Method: method, Href: uri, Body: body})
+	if err != nil {
+		return types.StringUnknown()
+	}
+	return types.StringValue(string(rb))
+}
+
+// plannedRequestsValue returns the planned_request JSON of requests apply
+// sends one after the other, as a list.
+func plannedRequestsValue(requests ...plannedRequest) types.String {
+	rb, err := json.Marshal(requests)
+	if err != nil {
+		return types.StringUnknown()
+	}
+	return types.StringValue(string(rb))
+}
+
+// appliedRequestValue is plannedRequestValue for Create and Update: a request
+// that is only decided while applying, e.g. adopting, is stored as null.
+func appliedRequestValue(v types.String) types.String {
+	if v.IsUnknown() {
+		return types.StringNull()
+	}
+	return v
+}
+
+// modifyPlanRequest sets planned_request in the plan of a create or update.
+// request is given what Create and Update read: the plan, or for a Create
+// reading its configuration, createFromConfig, the configuration.
+// planned_request is left unknown while the configuration has unknown values.
+func modifyPlanRequest[T any](ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, createFromConfig bool, request func(data *T, ctx context.Context, create bool) types.String) {
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	create := req.State.Raw.IsNull()
+	data := new(T)
+	if create && createFromConfig {
+		resp.Diagnostics.Append(req.Config.Get(ctx, data)...)
+	} else {
+		resp.Diagnostics.Append(req.Plan.Get(ctx, data)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned := request(data, ctx, create)
+	tflog.Debug(ctx, "modifyPlanRequest: planned request", map[string]interface{}{"planned_request": planned.String()})
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("planned_request"), planned)...)
+}
+
+// forEachParallel calls fn for every index below n, with at most limit calls
+// running at once, and returns when all of them are done. fn stores its result
+// by index so that results keep the order of the input.
+func forEachParallel(limit int, n int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
 func Find(what string, where []types.String) (idx int) {
 	for i, v := range where {
 		if v.ValueString() == what {
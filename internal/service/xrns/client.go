@@ -4,7 +4,7 @@ import (
 	"context"
 	"log"
 
-	pb "terraform-provider-xrcm/internal/service/xrns/pb"
+	pb "github.com/infinera/terraform-provider-xr/internal/service/xrns/pb"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -0,0 +1,77 @@
+package xrcm_pf
+
+import (
+	"sync"
+
+	"github.com/google/martian/v3/log"
+)
+
+// responseCache keeps device GET responses for the lifetime of the client,
+// which is one plan or apply. Concurrent fetches of the same URL share one
+// request, and writing to a device drops all of that device's entries.
+// Callers polling a device for a change use refresh, see
+// ExecuteDeviceHttpCommandNoCache.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string]*cacheEntry // device id -> command uri -> entry
+}
+
+type cacheEntry struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]map[string]*cacheEntry)}
+}
+
+// get returns the cached response for the device command, calling fetch if
+// there is none yet. Callers must not modify the returned body. Failed
+// fetches are not kept, so the next caller tries again.
+func (rc *responseCache) get(deviceid, commanduri string, fetch func() ([]byte, error)) ([]byte, error) {
+	rc.mu.Lock()
+	device, ok := rc.entries[deviceid]
+	if !ok {
+		device = make(map[string]*cacheEntry)
+		rc.entries[deviceid] = device
+	}
+	if e, ok := device[commanduri]; ok {
+		rc.mu.Unlock()
+		<-e.done
+		log.Debugf("responseCache: hit device = %s, uri = %s", deviceid, commanduri)
+		return e.body, e.err
+	}
+	e := &cacheEntry{done: make(chan struct{})}
+	device[commanduri] = e
+	rc.mu.Unlock()
+
+	e.body, e.err = fetch()
+	close(e.done)
+
+	if e.err != nil {
+		rc.mu.Lock()
+		if rc.entries[deviceid][commanduri] == e {
+			delete(rc.entries[deviceid], commanduri)
+		}
+		rc.mu.Unlock()
+	}
+	return e.body, e.err
+}
+
+// refresh fetches the device command anew, even if its response is cached,
+// and keeps the new response for the next callers of get.
+func (rc *responseCache) refresh(deviceid, commanduri string, fetch func() ([]byte, error)) ([]byte, error) {
+	rc.mu.Lock()
+	delete(rc.entries[deviceid], commanduri)
+	rc.mu.Unlock()
+	return rc.get(deviceid, commanduri, fetch)
+}
+
+// invalidate drops every cached response of the device.
+func (rc *responseCache) invalidate(deviceid string) {
+	rc.mu.Lock()
+	delete(rc.entries, deviceid)
+	rc.mu.Unlock()
+	log.Debugf("responseCache: invalidate device = %s", deviceid)
+}
@@ -0,0 +1,106 @@
+package xrcm_pf
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResponseCacheGetCoalesces(t *testing.T) {
+	rc := newResponseCache()
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return []byte("body"), nil
+	}
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 8)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b, err := rc.get("dev", "resources/cfg", fetch)
+			if err != nil {
+				t.Errorf("get: unexpected error %v", err)
+			}
+			bodies[i] = string(b)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("fetches = %d, want 1", n)
+	}
+	for i, b := range bodies {
+		if b != "body" {
+			t.Errorf("bodies[%d] = %q, want body", i, b)
+		}
+	}
+}
+
+func TestResponseCacheGetDoesNotKeepErrors(t *testing.T) {
+	rc := newResponseCache()
+	fetches := 0
+	fail := func() ([]byte, error) {
+		fetches++
+		return nil, errors.New("status: 500")
+	}
+	ok := func() ([]byte, error) {
+		fetches++
+		return []byte("body"), nil
+	}
+
+	if _, err := rc.get("dev", "resources/cfg", fail); err == nil {
+		t.Fatal("get: expected the fetch error")
+	}
+	b, err := rc.get("dev", "resources/cfg", ok)
+	if err != nil || string(b) != "body" {
+		t.Fatalf("get = %q, %v, want body after a failed fetch", b, err)
+	}
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want 2", fetches)
+	}
+}
+
+func TestResponseCacheInvalidate(t *testing.T) {
+	rc := newResponseCache()
+	value := "old"
+	fetch := func() ([]byte, error) { return []byte(value), nil }
+
+	rc.get("dev", "resources/cfg", fetch)
+	rc.get("other", "resources/cfg", fetch)
+	value = "new"
+
+	if b, _ := rc.get("dev", "resources/cfg", fetch); string(b) != "old" {
+		t.Errorf("get before invalidate = %q, want old", b)
+	}
+	rc.invalidate("dev")
+	if b, _ := rc.get("dev", "resources/cfg", fetch); string(b) != "new" {
+		t.Errorf("get after invalidate = %q, want new", b)
+	}
+	if b, _ := rc.get("other", "resources/cfg", fetch); string(b) != "old" {
+		t.Errorf("get of another device = %q, want old", b)
+	}
+}
+
+func TestResponseCacheRefresh(t *testing.T) {
+	rc := newResponseCache()
+	value := "old"
+	fetch := func() ([]byte, error) { return []byte(value), nil }
+
+	rc.get("dev", "resources/cfg", fetch)
+	value = "new"
+
+	if b, _ := rc.refresh("dev", "resources/cfg", fetch); string(b) != "new" {
+		t.Errorf("refresh = %q, want new", b)
+	}
+	value = "newer"
+	if b, _ := rc.get("dev", "resources/cfg", fetch); string(b) != "new" {
+		t.Errorf("get after refresh = %q, want the refreshed new", b)
+	}
+}
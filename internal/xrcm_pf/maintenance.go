@@ -0,0 +1,238 @@
+package xrcm_pf
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/martian/v3/log"
+)
+
+// ErrMaintenanceWindow is returned for write commands outside the maintenance
+// windows.
+var ErrMaintenanceWindow = errors.New("outside maintenance windows")
+
+// MaintenanceWindows restricts write commands to maintenance windows.
+type MaintenanceWindows struct {
+	// Location is the time zone of the windows.
+	Location *time.Location
+	Windows  []MaintenanceWindow
+	// ResourceTypes and Devices, when set, restrict only the commands on these
+	// resource types, e.g. xrcm_carrier, and on the devices whose name matches
+	// one of these regular expressions, as AllowedDevices of the Client.
+	ResourceTypes []string
+	Devices       []*regexp.Regexp
+	// Override lets write commands through outside the windows, each of them
+	// is logged with OverrideReason.
+	Override       bool
+	OverrideReason string
+}
+
+// MaintenanceWindow is either a recurring window, starting whenever Schedule
+// matches and lasting Duration, or the absolute window from Start to End.
+type MaintenanceWindow struct {
+	Schedule *Schedule
+	Duration time.Duration
+	Start    time.Time
+	End      time.Time
+}
+
+// Open reports whether the window is open at t.
+func (w MaintenanceWindow) Open(t time.Time) bool {
+	if w.Schedule == nil {
+		return !t.Before(w.Start) && t.Before(w.End)
+	}
+	// The window is open if it started in the last Duration.
+	start := t.Truncate(time.Minute)
+	for s := start; t.Sub(s) < w.Duration; s = s.Add(-time.Minute) {
+		if w.Schedule.Matches(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// restricts reports whether the windows apply to a command on a resource type
+// of a device. A command not addressed to a device, devicename empty, is
+// restricted whatever Devices.
+func (m *MaintenanceWindows) restricts(resourceType, devicename string) bool {
+	if len(m.ResourceTypes) > 0 {
+		found := false
+		for _, t := range m.ResourceTypes {
+			if t == resourceType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(m.Devices) > 0 && len(devicename) > 0 {
+		for _, re := range m.Devices {
+			if re.MatchString(devicename) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Check returns ErrMaintenanceWindow for a write command at t, on the resource
+// type addressed by uri, outside the windows that apply to it.
+func (m *MaintenanceWindows) Check(t time.Time, devicename, resourceType, command, uri string) error {
+	if command == "GET" {
+		return nil
+	}
+	if !m.restricts(resourceType, devicename) {
+		return nil
+	}
+
+	t = t.In(m.Location)
+	for _, w := range m.Windows {
+		if w.Open(t) {
+			return nil
+		}
+	}
+
+	if m.Override {
+		log.Infof("maintenance windows: override, allowing %s %s on device %s at %s: %s", command, uri, devicename, t.Format(time.RFC3339), m.OverrideReason)
+		return nil
+	}
+	log.Errorf("maintenance windows: refusing %s %s on device %s at %s", command, uri, devicename, t.Format(time.RFC3339))
+	return fmt.Errorf("%w: refusing %s %s on device %s at %s, set XR_MAINTENANCE_OVERRIDE to apply it anyway", ErrMaintenanceWindow, command, uri, devicename, t.Format(time.RFC3339))
+}
+
+// ResourceType returns the resource type, e.g. xrcm_carrier_diag, addressed
+// by a device command uri, or an empty string if it is not known. types maps
+// the collections of the hrefs, e.g. carriers, and their diagnostic, e.g.
+// carriers/diagnostic, to the resource types of the provider.
+func ResourceType(types map[string]string, uri string) string {
+	resourceType, collection := "", ""
+	for _, s := range strings.Split(strings.Trim(uri, "/"), "/") {
+		if s == "diagnostic" && len(collection) > 0 {
+			resourceType = types[collection+"/diagnostic"]
+			continue
+		}
+		if t, ok := types[s]; ok {
+			resourceType, collection = t, s
+		}
+	}
+	return resourceType
+}
+
+// Schedule is a cron like schedule of five fields: minute, hour, day of month,
+// month and day of week.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+var (
+	monthNames = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	dowNames   = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+// ParseSchedule parses a cron like schedule, e.g. "0 2 * * SAT". Fields are
+// *, numbers, names of months and days, ranges, lists and */n or a-b/n steps.
+func ParseSchedule(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q: expected 5 fields, minute hour day-of-month month day-of-week, got %d", spec, len(fields))
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("schedule %q: minute: %w", spec, err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("schedule %q: hour: %w", spec, err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("schedule %q: day of month: %w", spec, err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("schedule %q: month: %w", spec, err)
+	}
+	// Both 0 and 7 are Sunday.
+	if s.dow, err = parseField(fields[4], 0, 7, dowNames); err != nil {
+		return nil, fmt.Errorf("schedule %q: day of week: %w", spec, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+	return &s, nil
+}
+
+// Matches reports whether the schedule matches the minute of t. As in cron, a
+// restricted day of month and day of week match either.
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 || s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// parseField returns the bits of the values a schedule field matches.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rng = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = parseValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = parseValue(bounds[1], names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
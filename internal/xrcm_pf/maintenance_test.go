@@ -0,0 +1,170 @@
+package xrcm_pf
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"0 2 * * SAT", false},
+		{"*/15 0-6 1,15 JAN-MAR mon-fri", false},
+		{"30 22 * * 7", false},
+		{"0 2 * *", true},
+		{"60 2 * * *", true},
+		{"0 24 * * *", true},
+		{"0 2 0 * *", true},
+		{"0 2 * 13 *", true},
+		{"0 2 * * FUNDAY", true},
+		{"*/0 2 * * *", true},
+		{"0 5-2 * * *", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSchedule(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	// 2026-10-17 is a Saturday.
+	sat := time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"0 2 * * SAT", sat, true},
+		{"0 2 * * SAT", sat.Add(time.Minute), false},
+		{"0 2 * * SUN", sat, false},
+		{"0 2 * * 7", sat.AddDate(0, 0, 1), true},
+		{"*/15 * * * *", sat.Add(45 * time.Minute), true},
+		{"*/15 * * * *", sat.Add(50 * time.Minute), false},
+		{"0 2 * OCT *", sat, true},
+		{"0 2 * NOV *", sat, false},
+		// A restricted day of month and day of week match either.
+		{"0 2 1 * SAT", sat, true},
+		{"0 2 17 * MON", sat, true},
+		{"0 2 1 * MON", sat, false},
+		// With either a star, both must match.
+		{"0 2 1 * *", sat, false},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): unexpected error %v", tt.spec, err)
+		}
+		if got := s.Matches(tt.t); got != tt.want {
+			t.Errorf("ParseSchedule(%q).Matches(%s) = %v, want %v", tt.spec, tt.t.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	s, err := ParseSchedule("0 22 * * SAT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recurring := MaintenanceWindow{Schedule: s, Duration: 4 * time.Hour}
+	start := time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC)
+	absolute := MaintenanceWindow{Start: start, End: start.Add(time.Hour)}
+
+	tests := []struct {
+		name string
+		w    MaintenanceWindow
+		t    time.Time
+		want bool
+	}{
+		{"recurring before", recurring, start.Add(-time.Minute), false},
+		{"recurring at start", recurring, start, true},
+		{"recurring past midnight", recurring, start.Add(3*time.Hour + 59*time.Minute), true},
+		{"recurring at end", recurring, start.Add(4 * time.Hour), false},
+		{"recurring other day", recurring, start.AddDate(0, 0, 1), false},
+		{"absolute before", absolute, start.Add(-time.Second), false},
+		{"absolute at start", absolute, start, true},
+		{"absolute at end", absolute, start.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.w.Open(tt.t); got != tt.want {
+				t.Errorf("Open(%s) = %v, want %v", tt.t.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowsCheck(t *testing.T) {
+	start := time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC)
+	m := &MaintenanceWindows{
+		Location:      time.UTC,
+		Windows:       []MaintenanceWindow{{Start: start, End: start.Add(time.Hour)}},
+		ResourceTypes: []string{"xrcm_carrier"},
+		Devices:       []*regexp.Regexp{regexp.MustCompile("^(?:core-.*)$")},
+	}
+	closed := start.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		t            time.Time
+		devicename   string
+		resourceType string
+		command      string
+		wantErr      bool
+	}{
+		{"GET", closed, "core-1", "xrcm_carrier", "GET", false},
+		{"open", start, "core-1", "xrcm_carrier", "PUT", false},
+		{"closed", closed, "core-1", "xrcm_carrier", "PUT", true},
+		{"other device", closed, "edge-1", "xrcm_carrier", "PUT", false},
+		{"other resource type", closed, "core-1", "xrcm_ethernet", "PUT", false},
+		{"not addressed to a device", closed, "", "xrcm_carrier", "POST", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Check(tt.t, tt.devicename, tt.resourceType, tt.command, "resources/lineptps/1/carriers/1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrMaintenanceWindow) {
+				t.Errorf("Check() error = %v, want ErrMaintenanceWindow", err)
+			}
+		})
+	}
+
+	m.Override, m.OverrideReason = true, "emergency"
+	if err := m.Check(closed, "core-1", "xrcm_carrier", "PUT", "resources/lineptps/1/carriers/1"); err != nil {
+		t.Errorf("Check() with override error = %v, want nil", err)
+	}
+}
+
+func TestResourceType(t *testing.T) {
+	types := map[string]string{
+		"lineptps":            "xrcm_LinePTP",
+		"carriers":            "xrcm_carrier",
+		"carriers/diagnostic": "xrcm_carrier_diag",
+		"otus":                "xrcm_otu",
+	}
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"resources/lineptps/1", "xrcm_LinePTP"},
+		{"resources/lineptps/1/carriers/1", "xrcm_carrier"},
+		{"resources/lineptps/1/carriers/1/diagnostic", "xrcm_carrier_diag"},
+		{"resource-links/lineptps/1/carriers/1/diagnostic", "xrcm_carrier_diag"},
+		{"resources/otus/1/diagnostic", ""},
+		{"resources/unknown/1", ""},
+	}
+	for _, tt := range tests {
+		if got := ResourceType(types, tt.uri); got != tt.want {
+			t.Errorf("ResourceType(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
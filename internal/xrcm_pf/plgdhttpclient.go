@@ -2,6 +2,7 @@ package xrcm_pf
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,12 +10,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	ns "terraform-provider-xrcm/internal/service/xrns"
-
 	"github.com/google/martian/v3/log"
 )
 
@@ -28,9 +29,33 @@ type Client struct {
 	Token         string
 	Auth          AuthStruct
 	Devicemap     map[string]string
+	devicemapMu   sync.Mutex
 	GetTimeout    time.Duration
 	DeleteTimeout time.Duration
 	UpdateTimeout time.Duration
+	// MaxConcurrency bounds the requests data sources send in parallel.
+	MaxConcurrency int
+	// ReadOnly refuses every command that is not a GET.
+	ReadOnly bool
+	// AllowTrafficImpact lets resources plan traffic affecting changes on
+	// modules with active LCs.
+	AllowTrafficImpact bool
+	// Maintenance, when set, refuses write commands outside its windows.
+	Maintenance *MaintenanceWindows
+	// ResourceTypes maps the collections of the device hrefs to the resource
+	// types of the provider, see ResourceType.
+	ResourceTypes map[string]string
+	// AllowedDevices, when set, and DeniedDevices restrict the device names
+	// the client resolves, see CheckDevice.
+	AllowedDevices []*regexp.Regexp
+	DeniedDevices  []*regexp.Regexp
+	// Resolvers resolve device names to ids in order, DefaultDeviceResolvers
+	// when not set.
+	Resolvers []DeviceResolver
+	cache     *responseCache
+	// resolveMu serializes the resolutions, so that concurrent lookups share
+	// one device discovery, without holding devicemapMu.
+	resolveMu sync.Mutex
 }
 
 // AuthStruct -
@@ -48,6 +73,35 @@ type AuthResponse struct {
 	Token_type    string `json:"token_type"`
 }
 
+// ErrReadOnly is returned for write commands when the client is read only.
+var ErrReadOnly = errors.New("read only mode")
+
+// checkWritable refuses write commands of a read only client and write
+// commands outside the maintenance windows.
+func (c *Client) checkWritable(devicename, command, uri string) error {
+	if command == "GET" {
+		return nil
+	}
+	on := uri
+	if len(devicename) > 0 {
+		on += " on device " + devicename
+	}
+	if c.ReadOnly {
+		log.Errorf("checkWritable: read only mode, refusing %s %s", command, on)
+		return fmt.Errorf("%w: refusing %s %s, the provider is configured with read_only", ErrReadOnly, command, on)
+	}
+	if c.Maintenance != nil {
+		return c.Maintenance.Check(time.Now(), devicename, c.ResourceType(uri), command, uri)
+	}
+	return nil
+}
+
+// ResourceType returns the resource type addressed by a device command uri,
+// see ResourceType.
+func (c *Client) ResourceType(uri string) string {
+	return ResourceType(c.ResourceTypes, uri)
+}
+
 // NewClient -
 func NewClient(host, username, password *string) (*Client, error) {
 	getTimeout, err := strconv.Atoi(os.Getenv("GET_TIMEOUT"))
@@ -76,6 +130,7 @@ func NewClient(host, username, password *string) (*Client, error) {
 		UpdateTimeout: time.Duration(updateTimeout) * time.Second,
 		GetTimeout:    time.Duration(getTimeout) * time.Second,
 		DeleteTimeout: time.Duration(deleteTimeout) * time.Second,
+		cache:         newResponseCache(),
 	}
 
 	if host != nil {
@@ -88,11 +143,11 @@ func NewClient(host, username, password *string) (*Client, error) {
 	}
 
 	c.Token = ar.Token
+	// Requests after sign in apply their own timeout, see doRequest.
+	c.HTTPClient.Timeout = 0
 	//fmt.Println("ar Token:" + ar.Token)
 	//fmt.Println("c Token:" + c.Token)
 
-	log.SetLevel(log.Debug)
-
 	return &c, nil
 }
 
@@ -101,15 +156,22 @@ func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	req.Header.Set("Authorization", c.Token)
 
+	// The timeout is set per request, the client is shared by concurrent requests.
+	var timeout time.Duration
 	if req.Method == "GET" {
-		c.HTTPClient.Timeout = c.GetTimeout
+		timeout = c.GetTimeout
 	} else if req.Method != "DELETE" {
-		c.HTTPClient.Timeout = c.UpdateTimeout
+		timeout = c.UpdateTimeout
 	} else {
-		c.HTTPClient.Timeout = c.DeleteTimeout
+		timeout = c.DeleteTimeout
+	}
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
 	}
 
-	log.Debugf("doRequest: method = %s, Timeout = %v", req.Method, c.HTTPClient.Timeout)
+	log.Debugf("doRequest: method = %s, Timeout = %v", req.Method, timeout)
 
 	res, err := c.HTTPClient.Do(req)
 
@@ -137,30 +199,71 @@ func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 // deviceid		deviceid associated, optional attribute; either devicename or deviceid is used
 func (c *Client) ExecuteDeviceHttpCommand(devicename string, command, commanduri string, commandBody []byte) (result []byte, deviceid string, err error) {
 
-	deviceid, found := c.GetDeviceIdFromName(devicename)
-	if !found {
-		return nil, devicename, errors.New("device not found : " + devicename)
+	if err := c.checkWritable(devicename, command, commanduri); err != nil {
+		return nil, devicename, err
 	}
-	log.Debugf("ExecuteDeviceHttpCommand:New HTTP Request %s/api/v1/devices/%s/%s/", c.HostURL, deviceid, commanduri)
-	// fmt.Println("deviceid:", deviceid, "command body"+string(commandBody))
-	req, err := http.NewRequest(command, fmt.Sprintf("%s/api/v1/devices/%s/%s/", c.HostURL, deviceid, commanduri), bytes.NewBuffer(commandBody))
-	log.Debugf("ExecuteDeviceHttpCommand: Create HTTP Request %v", req)
+
+	deviceid, err = c.ResolveDevice(devicename)
 	if err != nil {
-		log.Errorf("ExecuteDeviceHttpCommand: Device ID = %s, Create New HTTP Request failed error %v", deviceid, err)
-		return nil, deviceid, err
+		return nil, devicename, err
 	}
 
-	body, err := c.doRequest(req)
+	body, err := c.executeCachedDeviceHttpCommand(deviceid, command, commanduri, commandBody)
+	return body, deviceid, err
+}
+
+// ExecuteDeviceHttpCommandNoCache is ExecuteDeviceHttpCommand for callers
+// polling a device for a change: a GET is always sent to the device, and its
+// response replaces the cached one.
+func (c *Client) ExecuteDeviceHttpCommandNoCache(devicename string, command, commanduri string, commandBody []byte) (result []byte, deviceid string, err error) {
+	if command != "GET" {
+		return c.ExecuteDeviceHttpCommand(devicename, command, commanduri, commandBody)
+	}
+
+	deviceid, err = c.ResolveDevice(devicename)
 	if err != nil {
-		log.Errorf("ExecuteDeviceHttpCommand: Send HTTP NewRequest failed for devide  error= %v", err)
-		return nil, deviceid, err
+		return nil, devicename, err
 	}
-	log.Debugf("ExecuteDeviceHttpCommand: Send HTTP Request SUCCESS. Response = %s", string(body))
+
+	body, err := c.cache.refresh(deviceid, commanduri, func() ([]byte, error) {
+		return c.executeDeviceHttpCommand(deviceid, command, commanduri, commandBody)
+	})
 	return body, deviceid, err
 }
 
+// ExecuteDeviceHttpCommandByID executes the command on the device with the given id.
+// GET responses are served from the client cache; any other command invalidates
+// the cached responses of the device.
 func (c *Client) ExecuteDeviceHttpCommandByID(deviceid string, command, commanduri string, commandBody []byte) (result []byte, err error) {
 
+	devicename, err := c.resolveDeviceName(deviceid)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.CheckDevice(devicename); err != nil {
+		return nil, err
+	}
+	if err := c.checkWritable(devicename, command, commanduri); err != nil {
+		return nil, err
+	}
+
+	return c.executeCachedDeviceHttpCommand(deviceid, command, commanduri, commandBody)
+}
+
+func (c *Client) executeCachedDeviceHttpCommand(deviceid string, command, commanduri string, commandBody []byte) (result []byte, err error) {
+	if command == "GET" {
+		return c.cache.get(deviceid, commanduri, func() ([]byte, error) {
+			return c.executeDeviceHttpCommand(deviceid, command, commanduri, commandBody)
+		})
+	}
+
+	body, err := c.executeDeviceHttpCommand(deviceid, command, commanduri, commandBody)
+	c.cache.invalidate(deviceid)
+	return body, err
+}
+
+func (c *Client) executeDeviceHttpCommand(deviceid string, command, commanduri string, commandBody []byte) (result []byte, err error) {
+
 	log.Debugf("ExecuteDeviceHttpCommand:New HTTP Request %s/api/v1/devices/%s/%s/", c.HostURL, deviceid, commanduri)
 	// fmt.Println("deviceid:", deviceid, "command body"+string(commandBody))
 	req, err := http.NewRequest(command, fmt.Sprintf("%s/api/v1/devices/%s/%s/", c.HostURL, deviceid, commanduri), bytes.NewBuffer(commandBody))
@@ -180,6 +283,14 @@ func (c *Client) ExecuteDeviceHttpCommandByID(deviceid string, command, commandu
 }
 
 func (c *Client) ExecuteHttpCommand(command, commanduri string, commandBody []byte) (result []byte, err error) {
+	// A command on devices/{deviceid}/... is a command on that device.
+	devicename := ""
+	if parts := strings.SplitN(commanduri, "/", 3); len(parts) > 1 && parts[0] == "devices" {
+		devicename = c.getDeviceNameFromId(parts[1])
+	}
+	if err := c.checkWritable(devicename, command, commanduri); err != nil {
+		return nil, err
+	}
 	// TODO: Remove the API base hardcoding
 	log.Debugf("ExecuteHttpCommand:New HTTP Request %s/api/v1/%s", c.HostURL, commanduri)
 	req, err := http.NewRequest(command, fmt.Sprintf("%s/api/v1/%s", c.HostURL, commanduri), bytes.NewBuffer(commandBody))
@@ -220,29 +331,27 @@ func getDevices(body []byte) (dev []map[string]interface{}) {
 	return devices
 }
 
+// DiscoverDevices adds the online devices of the CM to deviceMap, ids by name.
 func (c *Client) DiscoverDevices(deviceMap *map[string]string) (err error) {
-	// Get Devices
-	// Store devices in map
-	// fmt.Println("getting devices")
 	log.Debugf("DiscoverDevices")
 	body, err := c.ExecuteHttpCommand("GET", "devices", nil)
-	// fmt.Println("Device list" + string(body))
 	if err != nil {
 		log.Errorf("DiscoverDevices: Can't get the devices error" + err.Error())
 		return
 	}
 
 	devices := getDevices(body)
-	c.Devicemap = make(map[string]string)
+	if *deviceMap == nil {
+		*deviceMap = make(map[string]string)
+	}
 
 	for _, v := range devices {
 		name, dId := getNameAndId(v)
 		if (name != "") && (dId != "") {
-			c.Devicemap[name] = dId
+			(*deviceMap)[name] = dId
 		}
 	}
-	//fmt.Println("Devices Found", c.Devicemap)
-	log.Debugf("DiscoverDevices: number of devices = %d", len(c.Devicemap))
+	log.Debugf("DiscoverDevices: number of devices = %d", len(*deviceMap))
 	return nil
 }
 
@@ -260,29 +369,145 @@ func getNameAndId(d map[string]interface{}) (string, string) {
 	return "", ""
 }
 
+// ErrDeviceNotFound is returned for a device name the CM does not resolve.
+var ErrDeviceNotFound = errors.New("device not found")
+
+// ErrDeviceNotAllowed is returned for a device name outside AllowedDevices or
+// in DeniedDevices.
+var ErrDeviceNotAllowed = errors.New("device not allowed")
+
+// CheckDevice returns ErrDeviceNotAllowed if the device name matches one of
+// DeniedDevices, or if AllowedDevices is set and it matches none of them.
+func (c *Client) CheckDevice(devicename string) error {
+	for _, re := range c.DeniedDevices {
+		if re.MatchString(devicename) {
+			log.Errorf("CheckDevice: device %s matches denied_devices %s", devicename, re)
+			return fmt.Errorf("%w: %q matches denied_devices %s", ErrDeviceNotAllowed, devicename, re)
+		}
+	}
+	if len(c.AllowedDevices) == 0 {
+		return nil
+	}
+	for _, re := range c.AllowedDevices {
+		if re.MatchString(devicename) {
+			return nil
+		}
+	}
+	log.Errorf("CheckDevice: device %s does not match allowed_devices", devicename)
+	return fmt.Errorf("%w: %q does not match allowed_devices", ErrDeviceNotAllowed, devicename)
+}
+
+// ResolveDevice returns the id of the named device. Devices the client may
+// not address are refused with ErrDeviceNotAllowed before they are looked up.
+func (c *Client) ResolveDevice(devicename string) (string, error) {
+	if err := c.CheckDevice(devicename); err != nil {
+		return "", err
+	}
+	dId, found := c.lookupDeviceId(devicename)
+	if !found {
+		return "", fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+	}
+	return dId, nil
+}
+
+// GetDeviceIdFromName returns the id of the named device, see ResolveDevice.
 func (c *Client) GetDeviceIdFromName(devicename string) (dev string, found bool) {
-	dId, ok := c.Devicemap[devicename]
-	if !ok {
-		// Invoke the XR Naming service if it's enabled via the environment variable
-		if ep, nsEnabled := os.LookupEnv("XRCM_NAMING_SERVICE"); nsEnabled {
-			nsClient := ns.XrnsClient{Endpoint: ep}
-			device, err := nsClient.GetDeviceByName(devicename)
-			if err != nil {
-				log.Errorf("Error: Failed device lookup - %v\n", err)
-				return devicename, false
+	dId, err := c.ResolveDevice(devicename)
+	if err != nil {
+		return devicename, false
+	}
+	return dId, true
+}
+
+// lookupDeviceId resolves the device name with the resolvers of the client,
+// in order.
+func (c *Client) lookupDeviceId(devicename string) (dev string, found bool) {
+	c.resolveMu.Lock()
+	dId, err := ResolverChain(c.resolvers()).Resolve(devicename)
+	c.resolveMu.Unlock()
+	if err != nil {
+		log.Errorf("lookupDeviceId: Failed device lookup - %v", err)
+		return devicename, false // not found
+	}
+
+	log.Debugf("lookupDeviceId: devicename = %s, ID = %s", devicename, dId)
+	c.mergeDevices(map[string]string{devicename: dId})
+	return dId, true // found
+}
+
+// resolvers returns the resolvers of the client, DefaultDeviceResolvers if
+// it has none. The caller holds resolveMu.
+func (c *Client) resolvers() []DeviceResolver {
+	if len(c.Resolvers) == 0 {
+		c.Resolvers = DefaultDeviceResolvers(c)
+	}
+	return c.Resolvers
+}
+
+// mergeDevices adds devices, ids by name, to the Devicemap of the client.
+func (c *Client) mergeDevices(devices map[string]string) {
+	c.devicemapMu.Lock()
+	defer c.devicemapMu.Unlock()
+
+	if c.Devicemap == nil {
+		c.Devicemap = make(map[string]string)
+	}
+	for name, dId := range devices {
+		c.Devicemap[name] = dId
+	}
+}
+
+// resolveDeviceName returns the name of the device with the given id, listing
+// the devices of the resolvers that list them if it is not known yet. An id
+// no resolver lists has an empty name, which allowed_devices and
+// denied_devices cannot check: it is refused when they are set.
+func (c *Client) resolveDeviceName(deviceid string) (string, error) {
+	if name := c.getDeviceNameFromId(deviceid); len(name) > 0 {
+		return name, nil
+	}
+
+	c.resolveMu.Lock()
+	resolvers := c.resolvers()
+	c.resolveMu.Unlock()
+	for _, r := range resolvers {
+		l, ok := deviceLister(r)
+		if !ok {
+			continue
+		}
+		c.resolveMu.Lock()
+		devices, err := l.List()
+		c.resolveMu.Unlock()
+		if err != nil {
+			log.Errorf("resolveDeviceName: resolver %s failed to list the devices, error %v", r.Name(), err)
+			continue
+		}
+		// Keep the names of all the ids listed, see getDeviceNameFromId.
+		c.mergeDevices(devices)
+		for name, dId := range devices {
+			if dId == deviceid {
+				log.Debugf("resolveDeviceName: ID = %s, devicename = %s, resolver = %s", deviceid, name, r.Name())
+				return name, nil
 			}
-			c.Devicemap[devicename] = device.GetId()
-			return device.GetId(), true
 		}
-
-		c.DiscoverDevices(&c.Devicemap)
-		dId, ok = c.Devicemap[devicename]
 	}
 
-	log.Debugf("GetDeviceIdFromName: devicename = %s, ID = %s", devicename, dId)
-	if ok {
-		return dId, true // found
+	if len(c.AllowedDevices) > 0 || len(c.DeniedDevices) > 0 {
+		log.Errorf("resolveDeviceName: unknown device id %s", deviceid)
+		return "", fmt.Errorf("%w: unknown device id %s, no resolver knows its name to check it against allowed_devices and denied_devices", ErrDeviceNotAllowed, deviceid)
 	}
+	return "", nil
+}
+
+// getDeviceNameFromId returns the name of a discovered device, or an empty
+// string if the device is not known.
+func (c *Client) getDeviceNameFromId(deviceid string) string {
+	c.devicemapMu.Lock()
+	defer c.devicemapMu.Unlock()
 
-	return devicename, false // not found
+	for name, dId := range c.Devicemap {
+		if dId == deviceid {
+			return name
+		}
+	}
+	return ""
 }
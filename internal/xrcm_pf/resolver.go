@@ -0,0 +1,270 @@
+package xrcm_pf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ns "github.com/infinera/terraform-provider-xr/internal/service/xrns"
+
+	"github.com/google/martian/v3/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeviceResolver resolves the id of a device from its name. Resolve returns
+// ErrDeviceNotFound for a name the resolver does not know, so that the next
+// resolver of a ResolverChain is tried.
+type DeviceResolver interface {
+	// Name is the name of the resolver in logs and errors, e.g. static.
+	Name() string
+	Resolve(devicename string) (string, error)
+}
+
+// DeviceLister is a DeviceResolver listing all the devices it knows at once,
+// ids by name. CachedResolver caches the whole list.
+type DeviceLister interface {
+	DeviceResolver
+	List() (map[string]string, error)
+}
+
+// deviceLister returns the DeviceLister of r, the resolver of a
+// CachedResolver.
+func deviceLister(r DeviceResolver) (DeviceLister, bool) {
+	if cr, ok := r.(*CachedResolver); ok {
+		r = cr.Resolver
+	}
+	l, ok := r.(DeviceLister)
+	return l, ok
+}
+
+// ResolveListed resolves a device name from the list of a DeviceLister, for
+// the Resolve of the DeviceListers.
+func ResolveListed(r DeviceLister, devicename string) (string, error) {
+	devices, err := r.List()
+	if err != nil {
+		return "", err
+	}
+	dId, ok := devices[devicename]
+	if !ok {
+		return "", fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+	}
+	return dId, nil
+}
+
+// StaticResolver resolves devices from a file, either a JSON object of ids by
+// name or a JSON list of objects with a name and an id or deviceid.
+type StaticResolver struct {
+	Path string
+}
+
+func (r *StaticResolver) Name() string {
+	return "static"
+}
+
+func (r *StaticResolver) Resolve(devicename string) (string, error) {
+	return ResolveListed(r, devicename)
+}
+
+func (r *StaticResolver) List() (map[string]string, error) {
+	b, err := os.ReadFile(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]string)
+	if err := json.Unmarshal(b, &devices); err == nil {
+		return devices, nil
+	}
+
+	var list []map[string]interface{}
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("%s is neither a JSON object of ids by name nor a JSON list of devices: %w", r.Path, err)
+	}
+	for _, d := range list {
+		name, _ := d["name"].(string)
+		dId, ok := d["id"].(string)
+		if !ok {
+			dId, _ = d["deviceid"].(string)
+		}
+		if (name != "") && (dId != "") {
+			devices[name] = dId
+		}
+	}
+	log.Debugf("StaticResolver: %s, number of devices = %d", r.Path, len(devices))
+	return devices, nil
+}
+
+// NamingServiceResolver resolves devices with the XR naming service at
+// Endpoint.
+type NamingServiceResolver struct {
+	Endpoint string
+}
+
+func (r *NamingServiceResolver) Name() string {
+	return "naming_service"
+}
+
+func (r *NamingServiceResolver) Resolve(devicename string) (string, error) {
+	nsClient := ns.XrnsClient{Endpoint: r.Endpoint}
+	device, err := nsClient.GetDeviceByName(devicename)
+	if status.Code(err) == codes.NotFound {
+		return "", fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+	}
+	if err != nil {
+		return "", err
+	}
+	return device.GetId(), nil
+}
+
+// DiscoveryResolver resolves the online devices the CM of Client lists.
+type DiscoveryResolver struct {
+	Client *Client
+}
+
+func (r *DiscoveryResolver) Name() string {
+	return "discovery"
+}
+
+func (r *DiscoveryResolver) Resolve(devicename string) (string, error) {
+	return ResolveListed(r, devicename)
+}
+
+func (r *DiscoveryResolver) List() (map[string]string, error) {
+	devices := make(map[string]string)
+	if err := r.Client.DiscoverDevices(&devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// CachedResolver caches the ids Resolver resolves for TTL, and the names it
+// does not know for NegativeTTL. A zero TTL keeps the ids as long as the
+// resolver, a zero NegativeTTL does not cache unknown names.
+type CachedResolver struct {
+	Resolver    DeviceResolver
+	TTL         time.Duration
+	NegativeTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]resolverEntry
+}
+
+type resolverEntry struct {
+	id      string // empty for an unknown name
+	expires time.Time
+}
+
+func (e resolverEntry) valid(now time.Time) bool {
+	return e.expires.IsZero() || now.Before(e.expires)
+}
+
+// NewCachedResolver returns r caching its ids for ttl and its unknown names
+// for negativeTTL.
+func NewCachedResolver(r DeviceResolver, ttl time.Duration, negativeTTL time.Duration) *CachedResolver {
+	return &CachedResolver{Resolver: r, TTL: ttl, NegativeTTL: negativeTTL}
+}
+
+func (r *CachedResolver) Name() string {
+	return r.Resolver.Name()
+}
+
+func (r *CachedResolver) Resolve(devicename string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[string]resolverEntry)
+	}
+
+	now := time.Now()
+	if e, ok := r.entries[devicename]; ok && e.valid(now) {
+		if len(e.id) == 0 {
+			return "", fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+		}
+		return e.id, nil
+	}
+
+	var expires time.Time
+	if r.TTL > 0 {
+		expires = now.Add(r.TTL)
+	}
+
+	var dId string
+	var err error
+	if l, ok := r.Resolver.(DeviceLister); ok {
+		var devices map[string]string
+		devices, err = l.List()
+		if err != nil {
+			return "", err
+		}
+		for name, id := range devices {
+			r.entries[name] = resolverEntry{id: id, expires: expires}
+		}
+		var found bool
+		if dId, found = devices[devicename]; !found {
+			err = fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+		}
+	} else {
+		dId, err = r.Resolver.Resolve(devicename)
+	}
+
+	switch {
+	case errors.Is(err, ErrDeviceNotFound):
+		if r.NegativeTTL > 0 {
+			r.entries[devicename] = resolverEntry{expires: now.Add(r.NegativeTTL)}
+		} else {
+			delete(r.entries, devicename)
+		}
+		return "", err
+	case err != nil:
+		return "", err
+	}
+	r.entries[devicename] = resolverEntry{id: dId, expires: expires}
+	return dId, nil
+}
+
+// ResolverChain resolves devices with the first of its resolvers knowing
+// them, in order.
+type ResolverChain []DeviceResolver
+
+func (c ResolverChain) Name() string {
+	names := make([]string, len(c))
+	for i, r := range c {
+		names[i] = r.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Resolve returns ErrDeviceNotFound if no resolver knows the device, or the
+// errors of the resolvers that failed.
+func (c ResolverChain) Resolve(devicename string) (string, error) {
+	var errs []string
+	for _, r := range c {
+		dId, err := r.Resolve(devicename)
+		if err == nil {
+			log.Debugf("ResolverChain: devicename = %s, ID = %s, resolver = %s", devicename, dId, r.Name())
+			return dId, nil
+		}
+		if !errors.Is(err, ErrDeviceNotFound) {
+			log.Errorf("ResolverChain: resolver %s failed to resolve %s, error %v", r.Name(), devicename, err)
+			errs = append(errs, r.Name()+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("%w : %s, %s", ErrDeviceNotFound, devicename, strings.Join(errs, "; "))
+	}
+	return "", fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+}
+
+// DefaultDeviceResolvers are the resolvers of a client without Resolvers: the
+// XR naming service if XRCM_NAMING_SERVICE is set, else the CM discovery.
+func DefaultDeviceResolvers(c *Client) []DeviceResolver {
+	if ep, nsEnabled := os.LookupEnv("XRCM_NAMING_SERVICE"); nsEnabled {
+		return []DeviceResolver{NewCachedResolver(&NamingServiceResolver{Endpoint: ep}, 0, 0)}
+	}
+	return []DeviceResolver{NewCachedResolver(&DiscoveryResolver{Client: c}, 0, 0)}
+}
@@ -0,0 +1,218 @@
+package xrcm_pf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func writeDevices(t *testing.T, devices string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "devices.json")
+	if err := os.WriteFile(p, []byte(devices), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestResolveDeviceName(t *testing.T) {
+	static := &StaticResolver{Path: writeDevices(t, `{"core-1": "id-1", "edge-1": "id-2"}`)}
+	c := &Client{
+		Resolvers:      []DeviceResolver{NewCachedResolver(static, 0, 0)},
+		AllowedDevices: []*regexp.Regexp{regexp.MustCompile("^core-.*$")},
+	}
+
+	name, err := c.resolveDeviceName("id-1")
+	if err != nil || name != "core-1" {
+		t.Fatalf("resolveDeviceName(id-1) = %q, %v, want core-1", name, err)
+	}
+	if got := c.getDeviceNameFromId("id-1"); got != "core-1" {
+		t.Errorf("getDeviceNameFromId(id-1) after resolveDeviceName = %q, want core-1", got)
+	}
+	if err := c.CheckDevice(name); err != nil {
+		t.Errorf("CheckDevice(%s): unexpected error %v", name, err)
+	}
+
+	if _, err := c.resolveDeviceName("id-3"); !errors.Is(err, ErrDeviceNotAllowed) {
+		t.Errorf("resolveDeviceName of an unknown id with allowed_devices error = %v, want ErrDeviceNotAllowed", err)
+	}
+
+	c.AllowedDevices = nil
+	if name, err := c.resolveDeviceName("id-3"); err != nil || name != "" {
+		t.Errorf("resolveDeviceName of an unknown id = %q, %v, want an empty name", name, err)
+	}
+}
+
+// countingResolver resolves the devices of its map, counting the calls.
+type countingResolver struct {
+	devices map[string]string
+	calls   int
+}
+
+func (r *countingResolver) Name() string {
+	return "counting"
+}
+
+func (r *countingResolver) Resolve(devicename string) (string, error) {
+	r.calls++
+	dId, ok := r.devices[devicename]
+	if !ok {
+		return "", fmt.Errorf("%w : %s", ErrDeviceNotFound, devicename)
+	}
+	return dId, nil
+}
+
+// countingLister lists the devices of its map, counting the lists.
+type countingLister struct {
+	countingResolver
+}
+
+func (r *countingLister) Resolve(devicename string) (string, error) {
+	return ResolveListed(r, devicename)
+}
+
+func (r *countingLister) List() (map[string]string, error) {
+	r.calls++
+	return r.devices, nil
+}
+
+func TestCachedResolverTTL(t *testing.T) {
+	inner := &countingResolver{devices: map[string]string{"core-1": "id-1"}}
+	r := NewCachedResolver(inner, 30*time.Millisecond, 0)
+
+	for i := 0; i < 2; i++ {
+		if dId, err := r.Resolve("core-1"); err != nil || dId != "id-1" {
+			t.Fatalf("Resolve(core-1) = %q, %v, want id-1", dId, err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("resolver called %d times within the ttl, want 1", inner.calls)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	inner.devices["core-1"] = "id-9"
+	if dId, err := r.Resolve("core-1"); err != nil || dId != "id-9" {
+		t.Errorf("Resolve(core-1) after the ttl = %q, %v, want id-9", dId, err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("resolver called %d times after the ttl, want 2", inner.calls)
+	}
+}
+
+func TestCachedResolverNoTTL(t *testing.T) {
+	inner := &countingResolver{devices: map[string]string{"core-1": "id-1"}}
+	r := NewCachedResolver(inner, 0, 0)
+
+	r.Resolve("core-1")
+	time.Sleep(10 * time.Millisecond)
+	if dId, err := r.Resolve("core-1"); err != nil || dId != "id-1" || inner.calls != 1 {
+		t.Errorf("Resolve(core-1) = %q, %v after %d calls, want id-1 after 1 call", dId, err, inner.calls)
+	}
+}
+
+func TestCachedResolverNegativeTTL(t *testing.T) {
+	inner := &countingResolver{devices: map[string]string{}}
+	r := NewCachedResolver(inner, 0, 30*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Resolve("edge-1"); !errors.Is(err, ErrDeviceNotFound) {
+			t.Fatalf("Resolve(edge-1) error = %v, want ErrDeviceNotFound", err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("resolver called %d times within the negative ttl, want 1", inner.calls)
+	}
+
+	// A device added is not seen before the negative ttl expires.
+	inner.devices["edge-1"] = "id-2"
+	if _, err := r.Resolve("edge-1"); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("Resolve(edge-1) within the negative ttl error = %v, want ErrDeviceNotFound", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if dId, err := r.Resolve("edge-1"); err != nil || dId != "id-2" {
+		t.Errorf("Resolve(edge-1) after the negative ttl = %q, %v, want id-2", dId, err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("resolver called %d times after the negative ttl, want 2", inner.calls)
+	}
+}
+
+func TestCachedResolverNoNegativeTTL(t *testing.T) {
+	inner := &countingResolver{devices: map[string]string{}}
+	r := NewCachedResolver(inner, 0, 0)
+
+	r.Resolve("edge-1")
+	r.Resolve("edge-1")
+	if inner.calls != 2 {
+		t.Errorf("resolver called %d times without a negative ttl, want 2", inner.calls)
+	}
+}
+
+func TestCachedResolverLister(t *testing.T) {
+	inner := &countingLister{countingResolver{devices: map[string]string{"core-1": "id-1", "core-2": "id-2"}}}
+	r := NewCachedResolver(inner, 0, 0)
+
+	for _, name := range []string{"core-1", "core-2"} {
+		if _, err := r.Resolve(name); err != nil {
+			t.Fatalf("Resolve(%s): unexpected error %v", name, err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("lister listed %d times, want 1 for all its devices", inner.calls)
+	}
+}
+
+// blockingResolver resolves every device once release is closed.
+type blockingResolver struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingResolver) Name() string {
+	return "blocking"
+}
+
+func (r *blockingResolver) Resolve(devicename string) (string, error) {
+	close(r.started)
+	<-r.release
+	return "id-" + devicename, nil
+}
+
+func TestLookupDoesNotBlockDeviceNames(t *testing.T) {
+	slow := &blockingResolver{started: make(chan struct{}), release: make(chan struct{})}
+	c := &Client{
+		Devicemap: map[string]string{"core-1": "id-1"},
+		Resolvers: []DeviceResolver{slow},
+	}
+
+	done := make(chan string)
+	go func() {
+		dId, _ := c.lookupDeviceId("core-2")
+		done <- dId
+	}()
+	<-slow.started
+
+	names := make(chan string)
+	go func() { names <- c.getDeviceNameFromId("id-1") }()
+	select {
+	case name := <-names:
+		if name != "core-1" {
+			t.Errorf("getDeviceNameFromId(id-1) = %q, want core-1", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("getDeviceNameFromId blocked behind a device resolution")
+	}
+
+	close(slow.release)
+	if dId := <-done; dId != "id-core-2" {
+		t.Errorf("lookupDeviceId(core-2) = %q, want id-core-2", dId)
+	}
+	if got := c.getDeviceNameFromId("id-core-2"); got != "core-2" {
+		t.Errorf("getDeviceNameFromId(id-core-2) = %q, want core-2", got)
+	}
+}
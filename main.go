@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 
-  "terraform-provider-xrcm/internal/provider"
+  "github.com/infinera/terraform-provider-xr/internal/provider"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
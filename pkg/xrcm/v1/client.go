@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/infinera/terraform-provider-xr/internal/xrcm_pf"
+)
+
+// ErrNotFound is returned when the device or the resource does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Requester sends requests to the XR CM API. *xrcm_pf.Client implements it;
+// tests and tools may provide their own.
+type Requester interface {
+	ExecuteDeviceHttpCommand(devicename string, command, commanduri string, commandBody []byte) (result []byte, deviceid string, err error)
+	ExecuteHttpCommand(command, commanduri string, commandBody []byte) (result []byte, err error)
+	GetDeviceIdFromName(devicename string) (dev string, found bool)
+}
+
+// Client is the typed XR CM API client.
+type Client struct {
+	r Requester
+}
+
+// NewClient signs in to the XR CM host and returns a client for it.
+func NewClient(host, username, password string) (*Client, error) {
+	c, err := xrcm_pf.NewClient(&host, &username, &password)
+	if err != nil {
+		return nil, err
+	}
+	c.Devicemap = make(map[string]string)
+	return NewClientWithRequester(c), nil
+}
+
+// NewClientWithRequester returns a client sending its requests through r,
+// e.g. the client already configured by the terraform provider.
+func NewClientWithRequester(r Requester) *Client {
+	return &Client{r: r}
+}
+
+// ResourceID identifies a device resource.
+type ResourceID struct {
+	Href string `json:"href"`
+}
+
+// response is the envelope of every device resource response.
+type response struct {
+	Data struct {
+		ResourceID ResourceID      `json:"resourceId"`
+		Content    json.RawMessage `json:"content"`
+	} `json:"data"`
+}
+
+// ResourceLink is an entry of the device resource-links.
+type ResourceLink struct {
+	Href          string   `json:"href"`
+	ResourceTypes []string `json:"resourceTypes"`
+	Interfaces    []string `json:"interfaces"`
+}
+
+type resourceLinks struct {
+	Resources []ResourceLink `json:"resources"`
+}
+
+// collection is the content of a collection resource, e.g. /lineptps.
+type collection struct {
+	Links []struct {
+		Href string `json:"href"`
+	} `json:"links"`
+}
+
+// createRequest is the resource-links body creating a resource.
+type createRequest struct {
+	Rep        interface{} `json:"rep"`
+	Interfaces []string    `json:"if"`
+	Types      []string    `json:"rt"`
+	Policy     struct {
+		Bm int `json:"bm"`
+	} `json:"p"`
+}
+
+func (c *Client) do(ctx context.Context, device, method, uri string, in interface{}) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: could not encode request: %w", method, uri, err)
+		}
+		body = b
+	}
+
+	result, _, err := c.r.ExecuteDeviceHttpCommand(device, method, uri, body)
+	if err != nil {
+		if errors.Is(err, xrcm_pf.ErrDeviceNotFound) {
+			return nil, fmt.Errorf("device %s: %w", device, ErrNotFound)
+		}
+		if strings.Contains(err.Error(), "status: 404") {
+			return nil, fmt.Errorf("%s %s: %w", method, uri, ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s %s: %w", method, uri, err)
+	}
+	return result, nil
+}
+
+// getResource decodes the content of the resource at href into out.
+func (c *Client) getResource(ctx context.Context, device, href string, out interface{}) error {
+	body, err := c.do(ctx, device, "GET", "resources"+href, nil)
+	if err != nil {
+		return err
+	}
+	return decodeContent(body, href, out)
+}
+
+// updateResource PUTs in to the resource at href and decodes the updated
+// content into out.
+func (c *Client) updateResource(ctx context.Context, device, href string, in, out interface{}) error {
+	body, err := c.do(ctx, device, "PUT", "resources"+href, in)
+	if err != nil {
+		return err
+	}
+	return decodeContent(body, href, out)
+}
+
+// createResource POSTs rep to the resource-links collection at href and
+// decodes the created content into out. It returns the href of the created
+// resource.
+func (c *Client) createResource(ctx context.Context, device, href string, rep interface{}, interfaces []string, rt string, bm int, out interface{}) (string, error) {
+	req := createRequest{Rep: rep, Interfaces: interfaces, Types: []string{rt}}
+	req.Policy.Bm = bm
+
+	body, err := c.do(ctx, device, "POST", "resource-links"+href, req)
+	if err != nil {
+		return "", err
+	}
+
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("POST %s: could not parse response: %w", href, err)
+	}
+	if len(resp.Data.ResourceID.Href) == 0 {
+		return "", fmt.Errorf("POST %s: response has no resource href", href)
+	}
+	return resp.Data.ResourceID.Href, decodeContent(body, href, out)
+}
+
+// get returns the content of the resource at href.
+func get[T any](ctx context.Context, c *Client, device, href string) (*T, error) {
+	out := new(T)
+	if err := c.getResource(ctx, device, href, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// update PUTs in to the resource at href and returns the updated content.
+func update[T any](ctx context.Context, c *Client, device, href string, in *T) (*T, error) {
+	out := new(T)
+	if err := c.updateResource(ctx, device, href, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// create creates rep in the collection at href and returns the href and the
+// content of the created resource.
+func create[T any](ctx context.Context, c *Client, device, href string, rep *T, interfaces []string, rt string, bm int) (string, *T, error) {
+	out := new(T)
+	created, err := c.createResource(ctx, device, href, rep, interfaces, rt, bm, out)
+	if err != nil {
+		return created, nil, err
+	}
+	return created, out, nil
+}
+
+// deleteResource removes the resource at href through its resource link.
+func (c *Client) deleteResource(ctx context.Context, device, href string) error {
+	_, err := c.do(ctx, device, "DELETE", "resource-links"+href, nil)
+	return err
+}
+
+// listResources returns the hrefs of the members of the collection at href.
+func (c *Client) listResources(ctx context.Context, device, href string) ([]string, error) {
+	var content collection
+	if err := c.getResource(ctx, device, href, &content); err != nil {
+		return nil, err
+	}
+	var hrefs []string
+	for _, l := range content.Links {
+		if len(l.Href) > 0 {
+			hrefs = append(hrefs, l.Href)
+		}
+	}
+	return hrefs, nil
+}
+
+func decodeContent(body []byte, href string, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("%s: could not parse response: %w", href, err)
+	}
+	if len(resp.Data.Content) == 0 || string(resp.Data.Content) == "null" {
+		return fmt.Errorf("%s: response has no content", href)
+	}
+	if err := json.Unmarshal(resp.Data.Content, out); err != nil {
+		return fmt.Errorf("%s: could not parse content: %w", href, err)
+	}
+	return nil
+}
+
+// ResourceLinks returns the resource links of the device.
+func (c *Client) ResourceLinks(ctx context.Context, device string) ([]ResourceLink, error) {
+	body, err := c.do(ctx, device, "GET", "resource-links", nil)
+	if err != nil {
+		return nil, err
+	}
+	var links resourceLinks
+	if err := json.Unmarshal(body, &links); err != nil {
+		return nil, fmt.Errorf("resource-links: could not parse response: %w", err)
+	}
+	return links.Resources, nil
+}
+
+// String returns a pointer to v.
+func String(v string) *string { return &v }
+
+// Int64 returns a pointer to v.
+func Int64(v int64) *int64 { return &v }
+
+// Bool returns a pointer to v.
+func Bool(v bool) *bool { return &v }
+
+// Float64 returns a pointer to v.
+func Float64(v float64) *float64 { return &v }
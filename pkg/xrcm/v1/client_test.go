@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRequester answers the GETs of a device with bodies by command uri.
+type fakeRequester struct {
+	bodies map[string]string
+	errs   map[string]error
+}
+
+func (f *fakeRequester) ExecuteDeviceHttpCommand(devicename string, command, commanduri string, commandBody []byte) ([]byte, string, error) {
+	if err, ok := f.errs[commanduri]; ok {
+		return nil, "", err
+	}
+	body, ok := f.bodies[commanduri]
+	if !ok {
+		return nil, "", fmt.Errorf("status: 404, %s", commanduri)
+	}
+	return []byte(body), "id-" + devicename, nil
+}
+
+func (f *fakeRequester) ExecuteHttpCommand(command, commanduri string, commandBody []byte) ([]byte, error) {
+	body, ok := f.bodies[commanduri]
+	if !ok {
+		return nil, fmt.Errorf("status: 404, %s", commanduri)
+	}
+	return []byte(body), nil
+}
+
+func (f *fakeRequester) GetDeviceIdFromName(devicename string) (string, bool) {
+	return "id-" + devicename, true
+}
+
+func TestDecodeContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"content", `{"data":{"content":{"aid":"1.1","lcCtrl":2}}}`, false},
+		{"not json", `<html>`, true},
+		{"no content", `{"data":{}}`, true},
+		{"null content", `{"data":{"content":null}}`, true},
+		{"wrong type", `{"data":{"content":{"aid":1}}}`, true},
+		{"fractional number", `{"data":{"content":{"aid":"1.1","lcCtrl":2.5}}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lc LC
+			err := decodeContent([]byte(tt.body), "/lcs/1", &lc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (lc.Aid == nil || *lc.Aid != "1.1" || lc.LcCtrl == nil || *lc.LcCtrl != 2) {
+				t.Errorf("decodeContent() = %+v", lc)
+			}
+		})
+	}
+}
+
+func TestGetCarrierMissingFields(t *testing.T) {
+	c := NewClientWithRequester(&fakeRequester{bodies: map[string]string{
+		"resources/lineptps/1/carriers/1": `{"data":{"content":{"aid":"1.1.1","modulation":"16QAM","baudRate":0,"capabilities":{"supportedModulations":"16QAM","maxBaud":66.5}}}}`,
+	}})
+
+	carrier, err := c.GetCarrier(context.Background(), "xr-hub", "1", "1")
+	if err != nil {
+		t.Fatalf("GetCarrier: unexpected error %v", err)
+	}
+	if carrier.Modulation == nil || *carrier.Modulation != "16QAM" {
+		t.Errorf("Modulation = %v, want 16QAM", carrier.Modulation)
+	}
+	if carrier.BaudRate == nil || *carrier.BaudRate != 0 {
+		t.Errorf("BaudRate = %v, want a zero value, not nil", carrier.BaudRate)
+	}
+	if carrier.HFrequency != nil {
+		t.Errorf("HFrequency = %v, want nil as the device did not return it", *carrier.HFrequency)
+	}
+	if carrier.Capabilities["supportedModulations"] != "16QAM" || carrier.Capabilities["maxBaud"] != 66.5 {
+		t.Errorf("Capabilities = %v", carrier.Capabilities)
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	c := NewClientWithRequester(&fakeRequester{errs: map[string]error{
+		"resources/cfg": errors.New("ExecuteDeviceHttpCommand: device not found : xr-hub"),
+	}})
+
+	if _, err := c.GetLC(context.Background(), "xr-hub", "/lcs/1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetLC of a missing LC error = %v, want ErrNotFound", err)
+	}
+	if _, err := c.GetCfg(context.Background(), "xr-hub"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("GetCfg of a failing device error = %v, want the request error", err)
+	}
+}
+
+func TestListResources(t *testing.T) {
+	c := NewClientWithRequester(&fakeRequester{bodies: map[string]string{
+		"resources/lcs": `{"data":{"content":{"links":[{"href":"/lcs/1"},{"href":""},{"href":"/lcs/2"}]}}}`,
+	}})
+
+	hrefs, err := c.ListLCs(context.Background(), "xr-hub")
+	if err != nil {
+		t.Fatalf("ListLCs: unexpected error %v", err)
+	}
+	if len(hrefs) != 2 || hrefs[0] != "/lcs/1" || hrefs[1] != "/lcs/2" {
+		t.Errorf("ListLCs = %v, want [/lcs/1 /lcs/2]", hrefs)
+	}
+}
+
+func TestResourceLinks(t *testing.T) {
+	c := NewClientWithRequester(&fakeRequester{bodies: map[string]string{
+		"resource-links": `{"resources":[{"href":"/lineptps/1/carriers/1","resourceTypes":["xr.carrier"]},{"href":"/ethernets/1","resourceTypes":[]}]}`,
+	}})
+
+	links, err := c.ResourceLinks(context.Background(), "xr-hub")
+	if err != nil {
+		t.Fatalf("ResourceLinks: unexpected error %v", err)
+	}
+	if len(links) != 2 || links[0].ResourceTypes[0] != "xr.carrier" || len(links[1].ResourceTypes) != 0 {
+		t.Errorf("ResourceLinks = %+v", links)
+	}
+}
+
+func TestListDevices(t *testing.T) {
+	c := NewClientWithRequester(&fakeRequester{bodies: map[string]string{
+		"devices": `{"result":{"id":"d1","name":"xr-hub","types":["xr.hub"],"manufacturerName":[{"value":"Infinera"}],"metadata":{"connection":{"status":"ONLINE"}},"data":{"content":{"sv":"6.1","piid":"p1"}}}}
+{"result":{"id":"d2","name":"xr-leaf","metadata":{"connection":{"status":"OFFLINE"}}}}
+`,
+	}})
+
+	devices, err := c.ListDevices(context.Background())
+	if err != nil {
+		t.Fatalf("ListDevices: unexpected error %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("ListDevices = %d devices, want 2", len(devices))
+	}
+	if d := devices[0]; d.Id != "d1" || !d.Online() || d.Manufacturer() != "Infinera" || d.Data.Content.Sv != "6.1" {
+		t.Errorf("devices[0] = %+v", d)
+	}
+	if d := devices[1]; d.Online() || d.Manufacturer() != "" {
+		t.Errorf("devices[1] = %+v, want offline without manufacturer", d)
+	}
+
+	if _, err := c.GetDevice(context.Background(), "xr-edge"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetDevice of an unknown device error = %v, want ErrNotFound", err)
+	}
+
+	c = NewClientWithRequester(&fakeRequester{bodies: map[string]string{"devices": `{"result":{"id":1}}`}})
+	if _, err := c.ListDevices(context.Background()); err == nil {
+		t.Error("ListDevices of a malformed stream: expected an error")
+	}
+}
+
+func TestListAlarms(t *testing.T) {
+	c := NewClientWithRequester(&fakeRequester{bodies: map[string]string{
+		"resources/alarms":   `{"data":{"content":{"alarms":[{"id":"1","resource":"/lineptps/1","severity":"critical","conditionType":"LOS"}],"links":[{"href":"/alarms/2"}]}}}`,
+		"resources/alarms/2": `{"data":{"content":{"resource":"/ethernets/1","severity":"minor","raisedTime":"2026-10-19T01:00:00Z"}}}`,
+	}})
+
+	alarms, err := c.ListAlarms(context.Background(), "xr-hub")
+	if err != nil {
+		t.Fatalf("ListAlarms: unexpected error %v", err)
+	}
+	if len(alarms) != 2 {
+		t.Fatalf("ListAlarms = %d alarms, want 2", len(alarms))
+	}
+	if a := alarms[0]; a.Href != "" || *a.Id != "1" || *a.Severity != "critical" || *a.ConditionType != "LOS" {
+		t.Errorf("alarms[0] = %+v", a)
+	}
+	if a := alarms[1]; a.Href != "/alarms/2" || a.Id != nil || *a.Resource != "/ethernets/1" || *a.RaisedTime != "2026-10-19T01:00:00Z" {
+		t.Errorf("alarms[1] = %+v", a)
+	}
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Device is a device registered in XR CM.
+type Device struct {
+	Id               string   `json:"id"`
+	Name             string   `json:"name"`
+	Types            []string `json:"types"`
+	ManufacturerName []struct {
+		Value string `json:"value"`
+	} `json:"manufacturerName"`
+	Metadata struct {
+		Connection struct {
+			Status string `json:"status"`
+		} `json:"connection"`
+	} `json:"metadata"`
+	Data struct {
+		Content struct {
+			Sv   string `json:"sv"`
+			Piid string `json:"piid"`
+		} `json:"content"`
+	} `json:"data"`
+}
+
+// Online reports whether the device is connected.
+func (d *Device) Online() bool {
+	return d.Metadata.Connection.Status == "ONLINE"
+}
+
+// Manufacturer returns the first manufacturer name of the device.
+func (d *Device) Manufacturer() string {
+	if len(d.ManufacturerName) == 0 {
+		return ""
+	}
+	return d.ManufacturerName[0].Value
+}
+
+// ListDevices returns all devices known to XR CM.
+func (c *Client) ListDevices(ctx context.Context) ([]Device, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	body, err := c.r.ExecuteHttpCommand("GET", "devices", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GET devices: %w", err)
+	}
+
+	// The devices come as a stream of {"result": {...}} objects.
+	var devices []Device
+	dec := json.NewDecoder(strings.NewReader(string(body)))
+	for {
+		var entity struct {
+			Result *Device `json:"result"`
+		}
+		if err := dec.Decode(&entity); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("GET devices: could not parse response: %w", err)
+		}
+		if entity.Result != nil {
+			devices = append(devices, *entity.Result)
+		}
+	}
+	return devices, nil
+}
+
+// GetDevice returns the device with the given name.
+func (c *Client) GetDevice(ctx context.Context, name string) (*Device, error) {
+	devices, err := c.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		if devices[i].Name == name {
+			return &devices[i], nil
+		}
+	}
+	return nil, fmt.Errorf("device %s: %w", name, ErrNotFound)
+}
@@ -0,0 +1,24 @@
+// Package v1 is a typed Go client for the XR CM device API.
+//
+// It wraps the HTTP client used by the terraform provider and decodes the
+// device responses into structs instead of map[string]interface{}, so
+// unexpected payloads surface as errors rather than panics. Devices are
+// addressed by name, as in the provider configuration:
+//
+//	c, err := v1.NewClient("https://xrcm.example.com", "user", "password")
+//	carrier, err := c.GetCarrier(ctx, "xr-hub", "1", "1")
+//	_, err = c.UpdateCarrier(ctx, "xr-hub", "1", "1", &v1.Carrier{Modulation: v1.String("16QAM")})
+//
+// Fields are pointers so that a nil field is left out of update requests;
+// only set the fields to be changed, the device rejects read only ones. A
+// field the device did not return is nil. Integer fields are int64: a
+// fractional value, or a value of another type, fails the call with a decode
+// error instead of being truncated.
+//
+// NewClient leaves the logging of the process alone; the HTTP client logs
+// through github.com/google/martian/v3/log, whose level is the caller's to
+// set.
+//
+// The provider data sources read the devices through this package; the
+// resources still build their requests with xrcm_pf.
+package v1
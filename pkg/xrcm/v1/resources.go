@@ -0,0 +1,252 @@
+package v1
+
+import (
+	"context"
+)
+
+func lineptpHref(lineptpid string) string {
+	return "/lineptps/" + lineptpid
+}
+
+func carrierHref(lineptpid, carrierid string) string {
+	return lineptpHref(lineptpid) + "/carriers/" + carrierid
+}
+
+func ethernetHref(ethernetid string) string {
+	return "/ethernets/" + ethernetid
+}
+
+func otuHref(otuid string) string {
+	return "/otus/" + otuid
+}
+
+// GetCfg returns the module configuration.
+func (c *Client) GetCfg(ctx context.Context, device string) (*Cfg, error) {
+	return get[Cfg](ctx, c, device, "/cfg")
+}
+
+// UpdateCfg updates the module configuration with the non nil fields of cfg.
+func (c *Client) UpdateCfg(ctx context.Context, device string, cfg *Cfg) (*Cfg, error) {
+	return update(ctx, c, device, "/cfg", cfg)
+}
+
+// GetPlatform returns the module platform information.
+func (c *Client) GetPlatform(ctx context.Context, device string) (*Platform, error) {
+	return get[Platform](ctx, c, device, "/oic/p")
+}
+
+// ListLinePTPs returns the hrefs of the line ports.
+func (c *Client) ListLinePTPs(ctx context.Context, device string) ([]string, error) {
+	return c.listResources(ctx, device, "/lineptps")
+}
+
+// GetLinePTP returns a line port.
+func (c *Client) GetLinePTP(ctx context.Context, device, lineptpid string) (*LinePTP, error) {
+	return get[LinePTP](ctx, c, device, lineptpHref(lineptpid))
+}
+
+// ListCarriers returns the hrefs of the carriers of a line port.
+func (c *Client) ListCarriers(ctx context.Context, device, lineptpid string) ([]string, error) {
+	return c.listResources(ctx, device, lineptpHref(lineptpid)+"/carriers")
+}
+
+// GetCarrier returns a carrier.
+func (c *Client) GetCarrier(ctx context.Context, device, lineptpid, carrierid string) (*Carrier, error) {
+	return get[Carrier](ctx, c, device, carrierHref(lineptpid, carrierid))
+}
+
+// UpdateCarrier updates a carrier with the non nil fields of carrier.
+func (c *Client) UpdateCarrier(ctx context.Context, device, lineptpid, carrierid string, carrier *Carrier) (*Carrier, error) {
+	return update(ctx, c, device, carrierHref(lineptpid, carrierid), carrier)
+}
+
+// ListDSCs returns the hrefs of the DSCs of a carrier.
+func (c *Client) ListDSCs(ctx context.Context, device, lineptpid, carrierid string) ([]string, error) {
+	return c.listResources(ctx, device, carrierHref(lineptpid, carrierid)+"/dscs")
+}
+
+// GetDSC returns a DSC.
+func (c *Client) GetDSC(ctx context.Context, device, lineptpid, carrierid, dscid string) (*DSC, error) {
+	return get[DSC](ctx, c, device, carrierHref(lineptpid, carrierid)+"/dscs/"+dscid)
+}
+
+// UpdateDSC updates a DSC with the non nil fields of dsc.
+func (c *Client) UpdateDSC(ctx context.Context, device, lineptpid, carrierid, dscid string, dsc *DSC) (*DSC, error) {
+	return update(ctx, c, device, carrierHref(lineptpid, carrierid)+"/dscs/"+dscid, dsc)
+}
+
+// ListDSCGs returns the hrefs of the DSCGs of a carrier.
+func (c *Client) ListDSCGs(ctx context.Context, device, lineptpid, carrierid string) ([]string, error) {
+	return c.listResources(ctx, device, carrierHref(lineptpid, carrierid)+"/dscgs")
+}
+
+// GetDSCG returns the DSCG at href, as returned by CreateDSCG or ListDSCGs.
+func (c *Client) GetDSCG(ctx context.Context, device, href string) (*DSCG, error) {
+	return get[DSCG](ctx, c, device, href)
+}
+
+// CreateDSCG creates a DSCG on a carrier and returns its href.
+func (c *Client) CreateDSCG(ctx context.Context, device, lineptpid, carrierid string, dscg *DSCG) (string, *DSCG, error) {
+	return create(ctx, c, device, carrierHref(lineptpid, carrierid)+"/dscgs", dscg, []string{"oic.if.baseline", "oic.if.rw", "oic.if.delete"}, "xr.carrier.dscg", 3)
+}
+
+// UpdateDSCG updates the DSCG at href with the non nil fields of dscg.
+func (c *Client) UpdateDSCG(ctx context.Context, device, href string, dscg *DSCG) (*DSCG, error) {
+	return update(ctx, c, device, href, dscg)
+}
+
+// DeleteDSCG deletes the DSCG at href.
+func (c *Client) DeleteDSCG(ctx context.Context, device, href string) error {
+	return c.deleteResource(ctx, device, href)
+}
+
+// ListEthernets returns the hrefs of the client ports.
+func (c *Client) ListEthernets(ctx context.Context, device string) ([]string, error) {
+	return c.listResources(ctx, device, "/ethernets")
+}
+
+// GetEthernet returns a client port.
+func (c *Client) GetEthernet(ctx context.Context, device, ethernetid string) (*Ethernet, error) {
+	return get[Ethernet](ctx, c, device, ethernetHref(ethernetid))
+}
+
+// UpdateEthernet updates a client port with the non nil fields of ethernet.
+func (c *Client) UpdateEthernet(ctx context.Context, device, ethernetid string, ethernet *Ethernet) (*Ethernet, error) {
+	return update(ctx, c, device, ethernetHref(ethernetid), ethernet)
+}
+
+// ListACs returns the hrefs of the ACs of a client port.
+func (c *Client) ListACs(ctx context.Context, device, ethernetid string) ([]string, error) {
+	return c.listResources(ctx, device, ethernetHref(ethernetid)+"/acs")
+}
+
+// GetAC returns the AC at href, as returned by CreateAC or ListACs.
+func (c *Client) GetAC(ctx context.Context, device, href string) (*AC, error) {
+	return get[AC](ctx, c, device, href)
+}
+
+// CreateAC creates an AC on a client port and returns its href.
+func (c *Client) CreateAC(ctx context.Context, device, ethernetid string, ac *AC) (string, *AC, error) {
+	return create(ctx, c, device, ethernetHref(ethernetid)+"/acs", ac, []string{"oic.if.baseline", "oic.if.rw"}, "xr.ethernet.ac", 1)
+}
+
+// UpdateAC updates the AC at href with the non nil fields of ac.
+func (c *Client) UpdateAC(ctx context.Context, device, href string, ac *AC) (*AC, error) {
+	return update(ctx, c, device, href, ac)
+}
+
+// DeleteAC deletes the AC at href.
+func (c *Client) DeleteAC(ctx context.Context, device, href string) error {
+	return c.deleteResource(ctx, device, href)
+}
+
+// ListLCs returns the hrefs of the local connections.
+func (c *Client) ListLCs(ctx context.Context, device string) ([]string, error) {
+	return c.listResources(ctx, device, "/lcs")
+}
+
+// GetLC returns the LC at href, as returned by CreateLC or ListLCs.
+func (c *Client) GetLC(ctx context.Context, device, href string) (*LC, error) {
+	return get[LC](ctx, c, device, href)
+}
+
+// CreateLC creates a local connection and returns its href.
+func (c *Client) CreateLC(ctx context.Context, device string, lc *LC) (string, *LC, error) {
+	return create(ctx, c, device, "/lcs", lc, []string{"oic.if.baseline", "oic.if.a"}, "xr.lc", 3)
+}
+
+// UpdateLC updates the LC at href with the non nil fields of lc.
+func (c *Client) UpdateLC(ctx context.Context, device, href string, lc *LC) (*LC, error) {
+	return update(ctx, c, device, href, lc)
+}
+
+// DeleteLC deletes the LC at href.
+func (c *Client) DeleteLC(ctx context.Context, device, href string) error {
+	return c.deleteResource(ctx, device, href)
+}
+
+// ListOTUs returns the hrefs of the OTUs.
+func (c *Client) ListOTUs(ctx context.Context, device string) ([]string, error) {
+	return c.listResources(ctx, device, "/otus")
+}
+
+// GetOTU returns an OTU.
+func (c *Client) GetOTU(ctx context.Context, device, otuid string) (*OTU, error) {
+	return get[OTU](ctx, c, device, otuHref(otuid))
+}
+
+// UpdateOTU updates an OTU with the non nil fields of otu.
+func (c *Client) UpdateOTU(ctx context.Context, device, otuid string, otu *OTU) (*OTU, error) {
+	return update(ctx, c, device, otuHref(otuid), otu)
+}
+
+// GetODU returns an ODU.
+func (c *Client) GetODU(ctx context.Context, device, otuid, oduid string) (*ODU, error) {
+	return get[ODU](ctx, c, device, otuHref(otuid)+"/odus/"+oduid)
+}
+
+// UpdateODU updates an ODU with the non nil fields of odu.
+func (c *Client) UpdateODU(ctx context.Context, device, otuid, oduid string, odu *ODU) (*ODU, error) {
+	return update(ctx, c, device, otuHref(otuid)+"/odus/"+oduid, odu)
+}
+
+// GetDiagnostic returns the diagnostic resource of the resource at href,
+// e.g. /lineptps/1/carriers/1.
+func (c *Client) GetDiagnostic(ctx context.Context, device, href string) (*Diagnostic, error) {
+	return get[Diagnostic](ctx, c, device, href+"/diagnostic")
+}
+
+// UpdateDiagnostic updates the diagnostic resource of the resource at href
+// with the non nil fields of diagnostic.
+func (c *Client) UpdateDiagnostic(ctx context.Context, device, href string, diagnostic *Diagnostic) (*Diagnostic, error) {
+	return update(ctx, c, device, href+"/diagnostic", diagnostic)
+}
+
+// GetLLDPCfg returns the LLDP configuration of a client port.
+func (c *Client) GetLLDPCfg(ctx context.Context, device, ethernetid string) (*LLDPCfg, error) {
+	return get[LLDPCfg](ctx, c, device, ethernetHref(ethernetid)+"/lldp-cfg")
+}
+
+// UpdateLLDPCfg updates the LLDP configuration of a client port with the
+// non nil fields of cfg.
+func (c *Client) UpdateLLDPCfg(ctx context.Context, device, ethernetid string, cfg *LLDPCfg) (*LLDPCfg, error) {
+	return update(ctx, c, device, ethernetHref(ethernetid)+"/lldp-cfg", cfg)
+}
+
+// GetHostNeighbors returns the LLDP neighbors of a client port.
+func (c *Client) GetHostNeighbors(ctx context.Context, device, ethernetid string) (*HostNeighbors, error) {
+	return get[HostNeighbors](ctx, c, device, ethernetHref(ethernetid)+"/host-neighbors")
+}
+
+// GetLineNeighbors returns the neighbors of a line port.
+func (c *Client) GetLineNeighbors(ctx context.Context, device, lineptpid string) (*LineNeighbors, error) {
+	return get[LineNeighbors](ctx, c, device, lineptpHref(lineptpid)+"/neighbors")
+}
+
+// ListAlarms returns the active alarms of the module, those listed in /alarms
+// and those /alarms links to.
+func (c *Client) ListAlarms(ctx context.Context, device string) ([]Alarm, error) {
+	var content struct {
+		Alarms []Alarm `json:"alarms"`
+		Links  []struct {
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	if err := c.getResource(ctx, device, "/alarms", &content); err != nil {
+		return nil, err
+	}
+
+	alarms := content.Alarms
+	for _, l := range content.Links {
+		if len(l.Href) == 0 {
+			continue
+		}
+		alarm, err := get[Alarm](ctx, c, device, l.Href)
+		if err != nil {
+			return nil, err
+		}
+		alarm.Href = l.Href
+		alarms = append(alarms, *alarm)
+	}
+	return alarms, nil
+}
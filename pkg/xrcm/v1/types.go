@@ -0,0 +1,241 @@
+package v1
+
+// Cfg is the module configuration, /cfg.
+type Cfg struct {
+	Aid                *string `json:"aid,omitempty"`
+	ConfiguredRole     *string `json:"configuredRole,omitempty"`
+	CurrentRole        *string `json:"currentRole,omitempty"`
+	RoleStatus         *string `json:"roleStatus,omitempty"`
+	SerdesRate         *string `json:"serdesRate,omitempty"`
+	TrafficMode        *string `json:"trafficMode,omitempty"`
+	TcMode             *bool   `json:"tcMode,omitempty"`
+	RestartAction      *string `json:"restartAction,omitempty"`
+	FactoryResetAction *bool   `json:"factoryResetAction,omitempty"`
+	Topology           *string `json:"topology,omitempty"`
+	HId                *string `json:"hId,omitempty"`
+	HPortId            *string `json:"hPortId,omitempty"`
+	ConfigState        *string `json:"configState,omitempty"`
+}
+
+// Platform is the module platform information, /oic/p.
+type Platform struct {
+	Mnfv          *string `json:"mnfv,omitempty"`
+	Mnmn          *string `json:"mnmn,omitempty"`
+	Mnhw          *string `json:"mnhw,omitempty"`
+	Mndt          *string `json:"mndt,omitempty"`
+	Mnsel         *string `json:"mnsel,omitempty"`
+	Clei          *string `json:"clei,omitempty"`
+	MacAddress    *string `json:"macAddress,omitempty"`
+	ConnectorType *string `json:"connectorType,omitempty"`
+	FormFactor    *string `json:"formFactor,omitempty"`
+}
+
+// LinePTP is a line port, /lineptps/{lineptpid}.
+type LinePTP struct {
+	Aid         *string `json:"aid,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// Carrier is a line carrier, /lineptps/{lineptpid}/carriers/{carrierid}.
+// Capabilities holds the decoded JSON values of the device, not only strings.
+type Carrier struct {
+	Aid                     *string                `json:"aid,omitempty"`
+	Modulation              *string                `json:"modulation,omitempty"`
+	HModulation             *string                `json:"hModulation,omitempty"`
+	OModulation             *string                `json:"oModulation,omitempty"`
+	ClientPortMode          *string                `json:"clientPortMode,omitempty"`
+	FecIterations           *string                `json:"fecIterations,omitempty"`
+	HFecIterations          *string                `json:"hFecIterations,omitempty"`
+	OFecIterations          *string                `json:"oFecIterations,omitempty"`
+	ConstellationFrequency  *int64                 `json:"constellationFrequency,omitempty"`
+	HFrequency              *int64                 `json:"hFrequency,omitempty"`
+	AConstellationFrequency *int64                 `json:"aConstellationFrequency,omitempty"`
+	OperatingFrequency      *int64                 `json:"operatingFrequency,omitempty"`
+	BaudRate                *int64                 `json:"baudRate,omitempty"`
+	TxCLPtarget             *int64                 `json:"txCLPtarget,omitempty"`
+	HTxCLPtarget            *int64                 `json:"hTxCLPtarget,omitempty"`
+	ATxCLPtarget            *int64                 `json:"aTxCLPtarget,omitempty"`
+	MaxDSCs                 *int64                 `json:"maxDSCs,omitempty"`
+	HMaxDSCs                *int64                 `json:"hMaxDSCs,omitempty"`
+	OMaxDSCs                *int64                 `json:"oMaxDSCs,omitempty"`
+	MaxTxDSCs               *int64                 `json:"maxTxDSCs,omitempty"`
+	HMaxTxDSCs              *int64                 `json:"hMaxTxDSCs,omitempty"`
+	OMaxTxDSCs              *int64                 `json:"oMaxTxDSCs,omitempty"`
+	AdvLineCtrl             *string                `json:"advLineCtrl,omitempty"`
+	SpectralBandwidth       *int64                 `json:"spectralBandwidth,omitempty"`
+	AllowedTxCDSCs          *int64                 `json:"allowedTxCDSCs,omitempty"`
+	HAllowedTxCDSCs         *int64                 `json:"hAllowedTxCDSCs,omitempty"`
+	AAllowedTxCDSCs         *int64                 `json:"aAllowedTxCDSCs,omitempty"`
+	AllowedRxCDSCs          *int64                 `json:"allowedRxCDSCs,omitempty"`
+	HAllowedRxCDSCs         *int64                 `json:"hAllowedRxCDSCs,omitempty"`
+	AAllowedRxCDSCs         *int64                 `json:"aAllowedRxCDSCs,omitempty"`
+	Capabilities            map[string]interface{} `json:"capabilities,omitempty"`
+	ConfigState             *string                `json:"configState,omitempty"`
+}
+
+// DSC is a digital subcarrier,
+// /lineptps/{lineptpid}/carriers/{carrierid}/dscs/{dscid}.
+type DSC struct {
+	Aid         *string `json:"aid,omitempty"`
+	CDsc        *int64  `json:"cDsc,omitempty"`
+	RelativeDPO *int64  `json:"relativeDPO,omitempty"`
+	TxStatus    *string `json:"txStatus,omitempty"`
+	RxStatus    *string `json:"rxStatus,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// DSCG is a digital subcarrier group,
+// /lineptps/{lineptpid}/carriers/{carrierid}/dscgs/{dscgid}. The CDSCs
+// fields are bitmasks, bit n set for subcarrier n.
+type DSCG struct {
+	Aid         *string `json:"aid,omitempty"`
+	TxCDSCs     *int64  `json:"txCDSCs,omitempty"`
+	RxCDSCs     *int64  `json:"rxCDSCs,omitempty"`
+	IdleCDSCs   *int64  `json:"idleCDSCs,omitempty"`
+	DscgCtrl    *int64  `json:"dscgCtrl,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// Ethernet is a client port, /ethernets/{ethernetid}.
+type Ethernet struct {
+	Aid         *string `json:"aid,omitempty"`
+	FecMode     *string `json:"fecMode,omitempty"`
+	FecType     *string `json:"fecType,omitempty"`
+	PortSpeed   *int64  `json:"portSpeed,omitempty"`
+	MaxPktLen   *int64  `json:"maxPktLen,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// AC is an attachment circuit, /ethernets/{ethernetid}/acs/{acid}.
+type AC struct {
+	Aid         *string `json:"aid,omitempty"`
+	Capacity    *int64  `json:"capacity,omitempty"`
+	AcCtrl      *int64  `json:"acCtrl,omitempty"`
+	Imc         *string `json:"imc,omitempty"`
+	ImcOuterVID *string `json:"imcOuterVID,omitempty"`
+	Emc         *string `json:"emc,omitempty"`
+	EmcOuterVID *string `json:"emcOuterVID,omitempty"`
+	MaxPktLen   *int64  `json:"maxPktLen,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// LC is a local connection, /lcs/{lcid}.
+type LC struct {
+	Aid            *string `json:"aid,omitempty"`
+	LcCtrl         *int64  `json:"lcCtrl,omitempty"`
+	Direction      *string `json:"direction,omitempty"`
+	ClientAid      *string `json:"clientAid,omitempty"`
+	LineAid        *string `json:"lineAid,omitempty"`
+	DscgAid        *string `json:"dscgAid,omitempty"`
+	RemoteModuleId *string `json:"remoteModuleId,omitempty"`
+	RemoteClientId *string `json:"remoteClientId,omitempty"`
+	ConfigState    *string `json:"configState,omitempty"`
+}
+
+// OTU is an OTU, /otus/{otuid}.
+type OTU struct {
+	Aid         *string `json:"aid,omitempty"`
+	Otutype     *string `json:"otutype,omitempty"`
+	Rate        *int64  `json:"rate,omitempty"`
+	RxTTI       *string `json:"rxTTI,omitempty"`
+	TxTTI       *string `json:"txTTI,omitempty"`
+	ExpectedTTI *string `json:"expectedTTI,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// ODU is an ODU, /otus/{otuid}/odus/{oduid}.
+type ODU struct {
+	Aid         *string `json:"aid,omitempty"`
+	OduType     *string `json:"oduType,omitempty"`
+	ConfigState *string `json:"configState,omitempty"`
+}
+
+// Diagnostic is the diagnostic resource of a carrier, DSC, ethernet, AC or
+// OTU, {href}/diagnostic. Each parent supports a subset of the fields. The
+// facPRBS results are reported while facPRBSMon is on.
+type Diagnostic struct {
+	Aid               *string  `json:"aid,omitempty"`
+	TermLB            *string  `json:"termLB,omitempty"`
+	TermLBDuration    *int64   `json:"termLBDuration,omitempty"`
+	FacLB             *string  `json:"facLB,omitempty"`
+	FacLBDuration     *int64   `json:"facLBDuration,omitempty"`
+	FacPRBSGen        *bool    `json:"facPRBSGen,omitempty"`
+	FacPRBSMon        *bool    `json:"facPRBSMon,omitempty"`
+	TermPRBSGen       *bool    `json:"termPRBSGen,omitempty"`
+	TermPRBSMon       *bool    `json:"termPRBSMon,omitempty"`
+	FacPRBSSyncStatus *string  `json:"facPRBSSyncStatus,omitempty"`
+	FacPRBSErrorCount *int64   `json:"facPRBSErrorCount,omitempty"`
+	FacPRBSBer        *float64 `json:"facPRBSBer,omitempty"`
+	ConfigState       *string  `json:"configState,omitempty"`
+}
+
+// LLDPCfg is the LLDP configuration of a client port,
+// /ethernets/{ethernetid}/lldp-cfg.
+type LLDPCfg struct {
+	Aid              *string `json:"aid,omitempty"`
+	AdminStatus      *string `json:"adminStatus,omitempty"`
+	GccFwd           *bool   `json:"gccFwd,omitempty"`
+	HostRxDrop       *bool   `json:"hostRxDrop,omitempty"`
+	TTLUsage         *bool   `json:"TTLUsage,omitempty"`
+	ClrStats         *bool   `json:"clrStats,omitempty"`
+	FlushHostDb      *bool   `json:"flushHostDb,omitempty"`
+	TooManyNeighbors *bool   `json:"tooManyNeighbors,omitempty"`
+	ConfigState      *string `json:"configState,omitempty"`
+}
+
+// HostNeighbors are the LLDP neighbors of a client port,
+// /ethernets/{ethernetid}/host-neighbors.
+type HostNeighbors struct {
+	Aid       string         `json:"aid"`
+	Neighbors []HostNeighbor `json:"neighbors"`
+}
+
+// HostNeighbor is an LLDP neighbor of a client port.
+type HostNeighbor struct {
+	LocalPortSourceMAC string `json:"localPortSourceMAC"`
+	ChassisIdSubtype   string `json:"chassisIdSubtype"`
+	ChassisId          string `json:"chassisId"`
+	PortIdSubtype      string `json:"portIdSubtype"`
+	PortId             string `json:"portId"`
+	PortDescr          string `json:"portDescr"`
+	SysName            string `json:"sysName"`
+	SysDescr           string `json:"sysDescr"`
+	SysTTL             int64  `json:"sysTTL"`
+	LldpPDU            string `json:"lldpPDU"`
+}
+
+// LineNeighbors are the neighbors of a line port, /lineptps/{lineptpid}/neighbors.
+type LineNeighbors struct {
+	DiscoveredNeighbors   []LineNeighbor `json:"discoveredneighbors"`
+	ControlPlaneNeighbors []LineNeighbor `json:"controlplaneneighbors"`
+}
+
+// LineNeighbor is a neighbor of a line port. ConState and
+// LastConStateChange are only set for control plane neighbors,
+// DiscoveredTime only for discovered ones.
+type LineNeighbor struct {
+	MacAddress             string `json:"macAddress"`
+	CurrentRole            string `json:"currentRole"`
+	ConstellationFrequency string `json:"constellationFrequency"`
+	DiscoveredTime         string `json:"discoveredTime,omitempty"`
+	ConState               string `json:"conState,omitempty"`
+	LastConStateChange     string `json:"lastConStateChange,omitempty"`
+}
+
+// Alarm is an active alarm of the module, either in the alarms of /alarms or
+// at its own href linked from /alarms.
+type Alarm struct {
+	// Href is the href of the alarm itself, empty for an alarm listed in
+	// /alarms.
+	Href string `json:"-"`
+	// Id identifies the alarm on the module.
+	Id *string `json:"id,omitempty"`
+	// Resource is the href of the resource raising the alarm, Aid its aid.
+	Resource      *string `json:"resource,omitempty"`
+	Aid           *string `json:"aid,omitempty"`
+	Severity      *string `json:"severity,omitempty"`
+	ConditionType *string `json:"conditionType,omitempty"`
+	// RaisedTime is RFC 3339.
+	RaisedTime  *string `json:"raisedTime,omitempty"`
+	Description *string `json:"description,omitempty"`
+}